@@ -182,7 +182,7 @@ func TestDeviceCommandArgs(t *testing.T) {
 		{name: "reverse", call: func(d Device) error { return d.Reverse(context.Background(), "tcp:9000", "tcp:8000") }, want: []string{"-s", "S", "reverse", "tcp:9000", "tcp:8000"}},
 		{name: "uninstall", call: func(d Device) error { return d.Uninstall(context.Background(), "com.example") }, want: []string{"-s", "S", "uninstall", "com.example"}},
 		{name: "tap", call: func(d Device) error { return d.Tap(context.Background(), 10, 20) }, want: []string{"-s", "S", "shell", "input", "tap", "10", "20"}},
-		{name: "keyevent", call: func(d Device) error { return d.GoHome(context.Background()) }, want: []string{"-s", "S", "shell", "input", "keyevent", "KEYCODE_HOME"}},
+		{name: "keyevent", call: func(d Device) error { return d.GoHome(context.Background()) }, want: []string{"-s", "S", "shell", "input", "keyevent", "3"}},
 		{name: "input text", call: func(d Device) error { return d.InputText(context.Background(), "hello world") }, want: []string{"-s", "S", "shell", "input", "text", "hello%sworld"}},
 		{name: "setprop", call: func(d Device) error { return d.SetProp(context.Background(), "debug.foo", "1") }, want: []string{"-s", "S", "shell", "setprop", "debug.foo", "1"}},
 		{name: "grant", call: func(d Device) error {
@@ -0,0 +1,418 @@
+package adb
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+)
+
+// APKInfo describes an APK on the host, as read by [ParseAPK] from its binary
+// AndroidManifest.xml and native library directories.
+type APKInfo struct {
+	Package     string
+	VersionName string
+	// VersionCode combines versionCodeMajor (upper 32 bits) and versionCode.
+	VersionCode int64
+	MinSDK      int
+	TargetSDK   int
+	// ABIs lists the native ABIs the APK ships libraries for (the lib/<abi>/
+	// directories). It is empty for APKs without native code.
+	ABIs []string
+	// Permissions lists the permissions the manifest requests.
+	Permissions []string
+}
+
+// SupportsABIs reports whether the APK can run on a device supporting abis
+// (for example the result of [Device.ABIs]). APKs without native libraries
+// run on any ABI.
+func (a APKInfo) SupportsABIs(abis []string) bool {
+	if len(a.ABIs) == 0 {
+		return true
+	}
+	for _, abi := range a.ABIs {
+		if slices.Contains(abis, abi) {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseAPK reads package metadata from the APK at path without installing it
+// or invoking aapt. It returns an error wrapping [ErrInvalidAPK] when the file
+// is not a well-formed APK.
+func ParseAPK(path string) (APKInfo, error) {
+	zr, err := zip.OpenReader(path)
+	if err != nil {
+		return APKInfo{}, fmt.Errorf("%w: %w", ErrInvalidAPK, err)
+	}
+	defer zr.Close()
+	return parseAPKZip(&zr.Reader)
+}
+
+func parseAPKZip(zr *zip.Reader) (APKInfo, error) {
+	var info APKInfo
+	var manifest *zip.File
+	for _, f := range zr.File {
+		if f.Name == "AndroidManifest.xml" {
+			manifest = f
+			continue
+		}
+		// lib/<abi>/<name>.so
+		if dir, file := path.Split(f.Name); strings.HasPrefix(dir, "lib/") && strings.HasSuffix(file, ".so") {
+			abi := strings.TrimSuffix(strings.TrimPrefix(dir, "lib/"), "/")
+			if abi != "" && !strings.Contains(abi, "/") && !slices.Contains(info.ABIs, abi) {
+				info.ABIs = append(info.ABIs, abi)
+			}
+		}
+	}
+	if manifest == nil {
+		return APKInfo{}, fmt.Errorf("%w: no AndroidManifest.xml", ErrInvalidAPK)
+	}
+	rc, err := manifest.Open()
+	if err != nil {
+		return APKInfo{}, fmt.Errorf("%w: %w", ErrInvalidAPK, err)
+	}
+	defer rc.Close()
+	// Manifests are small; cap the read so a hostile archive cannot exhaust
+	// memory.
+	data, err := io.ReadAll(io.LimitReader(rc, 16<<20))
+	if err != nil {
+		return APKInfo{}, fmt.Errorf("%w: %w", ErrInvalidAPK, err)
+	}
+	if err := parseManifest(data, &info); err != nil {
+		return APKInfo{}, fmt.Errorf("%w: %w", ErrInvalidAPK, err)
+	}
+	return info, nil
+}
+
+// ABIs returns the native ABIs the device supports, most preferred first,
+// from `ro.product.cpu.abilist` (falling back to `ro.product.cpu.abi` on
+// devices that predate the list).
+func (d Device) ABIs(ctx context.Context) ([]string, error) {
+	list, err := d.GetProp(ctx, "ro.product.cpu.abilist")
+	if err != nil {
+		return nil, err
+	}
+	if list == "" {
+		if list, err = d.GetProp(ctx, "ro.product.cpu.abi"); err != nil {
+			return nil, err
+		}
+	}
+	return splitList(list), nil
+}
+
+// splitList splits a comma-separated property value, dropping empty entries.
+func splitList(s string) []string {
+	var out []string
+	for v := range strings.SplitSeq(s, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+// CheckABI returns an error wrapping [ErrIncompatibleABI] if the APK ships
+// native libraries for none of the device's ABIs, so callers can refuse an
+// install before transferring the APK.
+func (d Device) CheckABI(ctx context.Context, apk APKInfo) error {
+	abis, err := d.ABIs(ctx)
+	if err != nil {
+		return err
+	}
+	if !apk.SupportsABIs(abis) {
+		return fmt.Errorf("%w: APK has %s, device supports %s", ErrIncompatibleABI,
+			strings.Join(apk.ABIs, ","), strings.Join(abis, ","))
+	}
+	return nil
+}
+
+// APKInstalled reports whether the APK's package is installed on the device
+// with the same version code, in which case installing it again is redundant.
+func (d Device) APKInstalled(ctx context.Context, apk APKInfo) (bool, error) {
+	info, err := d.PackageInfo(ctx, apk.Package)
+	if errors.Is(err, ErrPackageNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return info.VersionCode == apk.VersionCode, nil
+}
+
+// --- binary XML (AXML) parsing ---
+
+// Binary XML chunk and value types, from
+// frameworks/base/libs/androidfw/include/androidfw/ResourceTypes.h.
+const (
+	resStringPoolType   = 0x0001
+	resXMLType          = 0x0003
+	resXMLStartElement  = 0x0102
+	resXMLResourceMap   = 0x0180
+	resStringPoolUTF8   = 1 << 8
+	resValueTypeString  = 0x03
+	resValueTypeIntDec  = 0x10
+	resValueTypeLastInt = 0x1f
+	resNoEntry          = 0xffffffff
+	chunkHeaderSize     = 8
+	xmlNodeHeaderSize   = 16
+	xmlAttrExtSize      = 20
+	xmlAttributeMinSize = 20
+)
+
+// Framework attribute resource ids (android.R.attr), which identify manifest
+// attributes even when an obfuscator has stripped their names.
+const (
+	attrIDName         = 0x01010003
+	attrIDVersionCode  = 0x0101021b
+	attrIDVersionName  = 0x0101021c
+	attrIDMinSDK       = 0x0101020c
+	attrIDTargetSDK    = 0x01010270
+	attrIDVersionMajor = 0x01010576
+)
+
+// axmlAttr is a decoded attribute of a start-element chunk.
+type axmlAttr struct {
+	name  string
+	resID uint32
+	str   string // string value, or "" for non-string values
+	num   uint32 // integer value for int/boolean types
+	isNum bool
+}
+
+// parseManifest walks the chunks of a binary AndroidManifest.xml and fills
+// the manifest-derived fields of info.
+func parseManifest(data []byte, info *APKInfo) error {
+	le := binary.LittleEndian
+	if len(data) < chunkHeaderSize || le.Uint16(data) != resXMLType {
+		return errors.New("manifest is not binary XML")
+	}
+	var (
+		pool    []string
+		resMap  []uint32
+		major   uint32
+		minor   uint32
+		sawRoot bool
+	)
+	off := int(le.Uint16(data[2:]))
+	for off+chunkHeaderSize <= len(data) {
+		typ := le.Uint16(data[off:])
+		size := int(le.Uint32(data[off+4:]))
+		if size < chunkHeaderSize || off+size > len(data) {
+			return fmt.Errorf("bad chunk size %d at offset %d", size, off)
+		}
+		chunk := data[off : off+size]
+		switch typ {
+		case resStringPoolType:
+			var err error
+			if pool, err = parseStringPool(chunk); err != nil {
+				return err
+			}
+		case resXMLResourceMap:
+			hdr := int(le.Uint16(chunk[2:]))
+			for i := hdr; i+4 <= len(chunk); i += 4 {
+				resMap = append(resMap, le.Uint32(chunk[i:]))
+			}
+		case resXMLStartElement:
+			name, attrs, err := parseStartElement(chunk, pool, resMap)
+			if err != nil {
+				return err
+			}
+			switch name {
+			case "manifest":
+				sawRoot = true
+				for _, a := range attrs {
+					switch {
+					case a.name == "package" && a.resID == 0:
+						info.Package = a.str
+					case a.is(attrIDVersionCode, "versionCode"):
+						minor = a.num
+					case a.is(attrIDVersionMajor, "versionCodeMajor"):
+						major = a.num
+					case a.is(attrIDVersionName, "versionName"):
+						info.VersionName = a.value()
+					}
+				}
+			case "uses-sdk":
+				for _, a := range attrs {
+					switch {
+					case a.is(attrIDMinSDK, "minSdkVersion"):
+						info.MinSDK, _ = strconv.Atoi(a.value())
+					case a.is(attrIDTargetSDK, "targetSdkVersion"):
+						info.TargetSDK, _ = strconv.Atoi(a.value())
+					}
+				}
+			case "uses-permission", "uses-permission-sdk-23", "uses-permission-sdk-m":
+				for _, a := range attrs {
+					if a.is(attrIDName, "name") && a.str != "" {
+						info.Permissions = append(info.Permissions, a.str)
+					}
+				}
+			}
+		}
+		off += size
+	}
+	if !sawRoot || info.Package == "" {
+		return errors.New("manifest has no package name")
+	}
+	info.VersionCode = int64(major)<<32 | int64(minor)
+	return nil
+}
+
+// is reports whether the attribute is the framework attribute with resource
+// id id, matching by name when the resource map does not identify it.
+func (a axmlAttr) is(id uint32, name string) bool {
+	if a.resID != 0 {
+		return a.resID == id
+	}
+	return a.name == name
+}
+
+// value renders the attribute as a string: the string value if it has one,
+// otherwise its integer value.
+func (a axmlAttr) value() string {
+	if a.isNum {
+		return strconv.FormatUint(uint64(a.num), 10)
+	}
+	return a.str
+}
+
+func parseStartElement(chunk []byte, pool []string, resMap []uint32) (string, []axmlAttr, error) {
+	le := binary.LittleEndian
+	if len(chunk) < xmlNodeHeaderSize+xmlAttrExtSize {
+		return "", nil, errors.New("truncated start element")
+	}
+	ext := chunk[xmlNodeHeaderSize:]
+	name := poolString(pool, le.Uint32(ext[4:]))
+	attrStart := int(le.Uint16(ext[8:]))
+	attrSize := int(le.Uint16(ext[10:]))
+	attrCount := int(le.Uint16(ext[12:]))
+	if attrSize < xmlAttributeMinSize {
+		return "", nil, errors.New("bad attribute size")
+	}
+	attrs := make([]axmlAttr, 0, attrCount)
+	for i := range attrCount {
+		at := attrStart + i*attrSize
+		if at+xmlAttributeMinSize > len(ext) {
+			return "", nil, errors.New("truncated attribute")
+		}
+		raw := ext[at:]
+		nameIdx := le.Uint32(raw[4:])
+		a := axmlAttr{name: poolString(pool, nameIdx)}
+		if int(nameIdx) < len(resMap) {
+			a.resID = resMap[nameIdx]
+		}
+		rawValue := le.Uint32(raw[8:])
+		dataType := raw[15]
+		data := le.Uint32(raw[16:])
+		switch {
+		case dataType == resValueTypeString:
+			a.str = poolString(pool, data)
+		case dataType >= resValueTypeIntDec && dataType <= resValueTypeLastInt:
+			a.num, a.isNum = data, true
+		case rawValue != resNoEntry:
+			a.str = poolString(pool, rawValue)
+		}
+		attrs = append(attrs, a)
+	}
+	return name, attrs, nil
+}
+
+func poolString(pool []string, idx uint32) string {
+	if int64(idx) >= int64(len(pool)) {
+		return ""
+	}
+	return pool[idx]
+}
+
+// parseStringPool decodes a ResStringPool chunk in either UTF-16 or UTF-8
+// encoding.
+func parseStringPool(chunk []byte) ([]string, error) {
+	le := binary.LittleEndian
+	if len(chunk) < 28 {
+		return nil, errors.New("truncated string pool")
+	}
+	hdr := int(le.Uint16(chunk[2:]))
+	count := int(le.Uint32(chunk[8:]))
+	flags := le.Uint32(chunk[16:])
+	start := int(le.Uint32(chunk[20:]))
+	if hdr+count*4 > len(chunk) || start > len(chunk) {
+		return nil, errors.New("truncated string pool")
+	}
+	pool := make([]string, count)
+	for i := range count {
+		at := start + int(le.Uint32(chunk[hdr+i*4:]))
+		if at >= len(chunk) {
+			return nil, errors.New("string offset out of range")
+		}
+		var err error
+		if flags&resStringPoolUTF8 != 0 {
+			pool[i], err = decodeUTF8String(chunk[at:])
+		} else {
+			pool[i], err = decodeUTF16String(chunk[at:])
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return pool, nil
+}
+
+// decodeUTF8String reads a UTF-8 pool entry: the UTF-16 length and the byte
+// length, each one or two bytes, followed by the bytes.
+func decodeUTF8String(b []byte) (string, error) {
+	_, n, ok := readLen8(b)
+	if !ok {
+		return "", errors.New("truncated string")
+	}
+	byteLen, m, ok := readLen8(b[n:])
+	if !ok || n+m+byteLen > len(b) {
+		return "", errors.New("truncated string")
+	}
+	return string(b[n+m : n+m+byteLen]), nil
+}
+
+func readLen8(b []byte) (length, size int, ok bool) {
+	if len(b) < 1 {
+		return 0, 0, false
+	}
+	if b[0]&0x80 == 0 {
+		return int(b[0]), 1, true
+	}
+	if len(b) < 2 {
+		return 0, 0, false
+	}
+	return int(b[0]&0x7f)<<8 | int(b[1]), 2, true
+}
+
+// decodeUTF16String reads a UTF-16 pool entry: a length in code units (one or
+// two uint16s), followed by the code units.
+func decodeUTF16String(b []byte) (string, error) {
+	le := binary.LittleEndian
+	if len(b) < 2 {
+		return "", errors.New("truncated string")
+	}
+	n, at := int(le.Uint16(b)), 2
+	if n&0x8000 != 0 {
+		if len(b) < 4 {
+			return "", errors.New("truncated string")
+		}
+		n, at = (n&0x7fff)<<16|int(le.Uint16(b[2:])), 4
+	}
+	if at+n*2 > len(b) {
+		return "", errors.New("truncated string")
+	}
+	units := make([]uint16, n)
+	for i := range units {
+		units[i] = le.Uint16(b[at+i*2:])
+	}
+	return string(utf16.Decode(units)), nil
+}
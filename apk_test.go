@@ -0,0 +1,292 @@
+package adb
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"unicode/utf16"
+)
+
+// axmlBuilder encodes a minimal binary AndroidManifest.xml for tests.
+type axmlBuilder struct {
+	utf8    bool
+	strings []string
+	resIDs  []uint32 // resource ids of the leading strings
+	body    bytes.Buffer
+}
+
+// str interns s, returning its pool index.
+func (b *axmlBuilder) str(s string) uint32 {
+	for i, have := range b.strings {
+		if have == s {
+			return uint32(i)
+		}
+	}
+	b.strings = append(b.strings, s)
+	return uint32(len(b.strings) - 1)
+}
+
+// attrName interns a framework attribute name with its resource id. Attribute
+// names must be interned before any other strings so their indices line up
+// with the resource map.
+func (b *axmlBuilder) attrName(s string, id uint32) uint32 {
+	idx := b.str(s)
+	if int(idx) == len(b.resIDs) {
+		b.resIDs = append(b.resIDs, id)
+	}
+	return idx
+}
+
+type testAttr struct {
+	name     uint32
+	dataType uint8
+	data     uint32
+	raw      uint32
+}
+
+func (b *axmlBuilder) strAttr(name uint32, value string) testAttr {
+	idx := b.str(value)
+	return testAttr{name: name, dataType: resValueTypeString, data: idx, raw: idx}
+}
+
+func intAttr(name, value uint32) testAttr {
+	return testAttr{name: name, dataType: resValueTypeIntDec, data: value, raw: resNoEntry}
+}
+
+func (b *axmlBuilder) start(name string, attrs ...testAttr) {
+	le := binary.LittleEndian
+	size := xmlNodeHeaderSize + xmlAttrExtSize + 20*len(attrs)
+	hdr := make([]byte, size)
+	le.PutUint16(hdr[0:], resXMLStartElement)
+	le.PutUint16(hdr[2:], xmlNodeHeaderSize)
+	le.PutUint32(hdr[4:], uint32(size))
+	le.PutUint32(hdr[12:], resNoEntry) // comment
+	ext := hdr[xmlNodeHeaderSize:]
+	le.PutUint32(ext[0:], resNoEntry) // namespace
+	le.PutUint32(ext[4:], b.str(name))
+	le.PutUint16(ext[8:], xmlAttrExtSize)
+	le.PutUint16(ext[10:], 20)
+	le.PutUint16(ext[12:], uint16(len(attrs)))
+	for i, a := range attrs {
+		at := ext[xmlAttrExtSize+i*20:]
+		le.PutUint32(at[0:], resNoEntry)
+		le.PutUint32(at[4:], a.name)
+		le.PutUint32(at[8:], a.raw)
+		le.PutUint16(at[12:], 8)
+		at[15] = a.dataType
+		le.PutUint32(at[16:], a.data)
+	}
+	b.body.Write(hdr)
+}
+
+func (b *axmlBuilder) bytes() []byte {
+	le := binary.LittleEndian
+	var strData bytes.Buffer
+	offsets := make([]uint32, len(b.strings))
+	for i, s := range b.strings {
+		offsets[i] = uint32(strData.Len())
+		if b.utf8 {
+			strData.WriteByte(byte(len([]rune(s))))
+			strData.WriteByte(byte(len(s)))
+			strData.WriteString(s)
+			strData.WriteByte(0)
+		} else {
+			units := utf16.Encode([]rune(s))
+			_ = binary.Write(&strData, le, uint16(len(units)))
+			_ = binary.Write(&strData, le, units)
+			_ = binary.Write(&strData, le, uint16(0))
+		}
+	}
+	for strData.Len()%4 != 0 {
+		strData.WriteByte(0)
+	}
+	poolHdr := 28
+	pool := make([]byte, poolHdr+4*len(b.strings))
+	le.PutUint16(pool[0:], resStringPoolType)
+	le.PutUint16(pool[2:], uint16(poolHdr))
+	le.PutUint32(pool[4:], uint32(len(pool)+strData.Len()))
+	le.PutUint32(pool[8:], uint32(len(b.strings)))
+	if b.utf8 {
+		le.PutUint32(pool[16:], resStringPoolUTF8)
+	}
+	le.PutUint32(pool[20:], uint32(len(pool)))
+	for i, o := range offsets {
+		le.PutUint32(pool[poolHdr+i*4:], o)
+	}
+	pool = append(pool, strData.Bytes()...)
+
+	resMap := make([]byte, chunkHeaderSize+4*len(b.resIDs))
+	le.PutUint16(resMap[0:], resXMLResourceMap)
+	le.PutUint16(resMap[2:], chunkHeaderSize)
+	le.PutUint32(resMap[4:], uint32(len(resMap)))
+	for i, id := range b.resIDs {
+		le.PutUint32(resMap[chunkHeaderSize+i*4:], id)
+	}
+
+	out := make([]byte, chunkHeaderSize)
+	le.PutUint16(out[0:], resXMLType)
+	le.PutUint16(out[2:], chunkHeaderSize)
+	out = append(out, pool...)
+	out = append(out, resMap...)
+	out = append(out, b.body.Bytes()...)
+	le.PutUint32(out[4:], uint32(len(out)))
+	return out
+}
+
+// testManifest encodes a manifest for com.example with two permissions.
+func testManifest(utf8 bool) []byte {
+	b := &axmlBuilder{utf8: utf8}
+	name := b.attrName("name", attrIDName)
+	versionCode := b.attrName("versionCode", attrIDVersionCode)
+	versionName := b.attrName("versionName", attrIDVersionName)
+	major := b.attrName("versionCodeMajor", attrIDVersionMajor)
+	minSDK := b.attrName("minSdkVersion", attrIDMinSDK)
+	// An obfuscated targetSdkVersion: the name is stripped but the resource
+	// id still identifies it.
+	target := b.attrName("", attrIDTargetSDK)
+	pkg := b.str("package")
+	b.start("manifest",
+		b.strAttr(pkg, "com.example"),
+		intAttr(versionCode, 42),
+		intAttr(major, 1),
+		b.strAttr(versionName, "1.2.3 β"),
+	)
+	b.start("uses-sdk", intAttr(minSDK, 21), intAttr(target, 34))
+	b.start("uses-permission", b.strAttr(name, "android.permission.INTERNET"))
+	b.start("uses-permission-sdk-23", b.strAttr(name, "android.permission.CAMERA"))
+	b.start("application", b.strAttr(name, "com.example.App"))
+	return b.bytes()
+}
+
+// writeTestAPK writes a zip containing the given entries and returns its path.
+func writeTestAPK(t *testing.T, entries map[string][]byte) string {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, data := range entries {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatalf("zip create: %v", err)
+		}
+		if _, err := w.Write(data); err != nil {
+			t.Fatalf("zip write: %v", err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatalf("zip close: %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "app.apk")
+	if err := os.WriteFile(path, buf.Bytes(), 0o600); err != nil {
+		t.Fatalf("write apk: %v", err)
+	}
+	return path
+}
+
+func TestParseAPK(t *testing.T) {
+	for _, utf8 := range []bool{false, true} {
+		name := "utf16"
+		if utf8 {
+			name = "utf8"
+		}
+		t.Run(name, func(t *testing.T) {
+			path := writeTestAPK(t, map[string][]byte{
+				"AndroidManifest.xml":       testManifest(utf8),
+				"classes.dex":               []byte("dex"),
+				"lib/arm64-v8a/libfoo.so":   nil,
+				"lib/arm64-v8a/libbar.so":   nil,
+				"lib/armeabi-v7a/libfoo.so": nil,
+				"lib/README":                nil,
+			})
+			got, err := ParseAPK(path)
+			if err != nil {
+				t.Fatalf("ParseAPK() error = %v", err)
+			}
+			if len(got.ABIs) != 2 {
+				t.Fatalf("ParseAPK().ABIs = %v, want 2 ABIs", got.ABIs)
+			}
+			got.ABIs = nil // zip order is not deterministic
+			want := APKInfo{
+				Package:     "com.example",
+				VersionName: "1.2.3 β",
+				VersionCode: 1<<32 | 42,
+				MinSDK:      21,
+				TargetSDK:   34,
+				Permissions: []string{"android.permission.INTERNET", "android.permission.CAMERA"},
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Fatalf("ParseAPK() = %#v, want %#v", got, want)
+			}
+		})
+	}
+}
+
+func TestParseAPK_Invalid(t *testing.T) {
+	tests := map[string]map[string][]byte{
+		"no manifest":     {"classes.dex": []byte("dex")},
+		"text manifest":   {"AndroidManifest.xml": []byte("<manifest package=\"x\"/>")},
+		"truncated chunk": {"AndroidManifest.xml": testManifest(false)[:100]},
+	}
+	for name, entries := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := ParseAPK(writeTestAPK(t, entries)); !errors.Is(err, ErrInvalidAPK) {
+				t.Fatalf("ParseAPK() error = %v, want ErrInvalidAPK", err)
+			}
+		})
+	}
+	notZip := filepath.Join(t.TempDir(), "x.apk")
+	if err := os.WriteFile(notZip, []byte("not a zip"), 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if _, err := ParseAPK(notZip); !errors.Is(err, ErrInvalidAPK) {
+		t.Fatalf("ParseAPK(not zip) error = %v, want ErrInvalidAPK", err)
+	}
+}
+
+func TestSupportsABIs(t *testing.T) {
+	device := []string{"arm64-v8a", "armeabi-v7a", "armeabi"}
+	if !(APKInfo{}).SupportsABIs(device) {
+		t.Fatal("APK without native code should support any ABI")
+	}
+	if !(APKInfo{ABIs: []string{"x86", "armeabi-v7a"}}).SupportsABIs(device) {
+		t.Fatal("APK with a matching ABI should be supported")
+	}
+	if (APKInfo{ABIs: []string{"x86_64"}}).SupportsABIs(device) {
+		t.Fatal("x86_64-only APK should not be supported on ARM")
+	}
+}
+
+func TestCheckABI(t *testing.T) {
+	c, argsFile := fakeADB(t, "arm64-v8a,armeabi-v7a,armeabi\n", "", 0)
+	d := c.Device("S")
+	if err := d.CheckABI(context.Background(), APKInfo{ABIs: []string{"arm64-v8a"}}); err != nil {
+		t.Fatalf("CheckABI() error = %v", err)
+	}
+	want := []string{"-s", "S", "shell", "getprop", "ro.product.cpu.abilist"}
+	if got := readArgs(t, argsFile); !reflect.DeepEqual(got, want) {
+		t.Fatalf("CheckABI() args = %v, want %v", got, want)
+	}
+	if err := d.CheckABI(context.Background(), APKInfo{ABIs: []string{"x86_64"}}); !errors.Is(err, ErrIncompatibleABI) {
+		t.Fatalf("CheckABI() error = %v, want ErrIncompatibleABI", err)
+	}
+}
+
+func TestAPKInstalled(t *testing.T) {
+	apk := APKInfo{Package: "com.example", VersionCode: 42}
+	c, _ := fakeADBSeq(t,
+		fakeResponse{stdout: packageDump},
+		fakeResponse{stdout: "package:/data/app/com.example/base.apk\n"},
+	)
+	if ok, err := c.Device("S").APKInstalled(context.Background(), apk); err != nil || !ok {
+		t.Fatalf("APKInstalled() = %v, %v; want true", ok, err)
+	}
+	c, _ = fakeADB(t, "Unable to find package: com.example\n", "", 0)
+	if ok, err := c.Device("S").APKInstalled(context.Background(), apk); err != nil || ok {
+		t.Fatalf("APKInstalled() = %v, %v; want false", ok, err)
+	}
+}
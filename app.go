@@ -0,0 +1,99 @@
+package adb
+
+import (
+	"context"
+	"regexp"
+	"strings"
+)
+
+// ForceStop stops every process of a package and cancels its alarms and jobs,
+// equivalent to `adb shell am force-stop <pkg>`.
+func (d Device) ForceStop(ctx context.Context, pkg string) error {
+	return d.execChecked(ctx, "shell", "am", "force-stop", pkg)
+}
+
+// Kill kills a package's background processes when it is safe to do so,
+// equivalent to `adb shell am kill <pkg>`. Unlike [Device.ForceStop] it leaves
+// foreground processes running.
+func (d Device) Kill(ctx context.Context, pkg string) error {
+	return d.execChecked(ctx, "shell", "am", "kill", pkg)
+}
+
+// ClearData deletes all data associated with a package, equivalent to
+// `adb shell pm clear <pkg>`.
+func (d Device) ClearData(ctx context.Context, pkg string) error {
+	args := []string{"shell", "pm", "clear", pkg}
+	res, err := d.runChecked(ctx, args...)
+	if err != nil {
+		return err
+	}
+	// pm clear prints "Failed" (not "Failure [...]") and exits 0 when the
+	// package does not exist, so require an explicit success report.
+	if !strings.Contains(res.StdoutString(), "Success") {
+		return &CommandError{
+			Args:   append([]string{"-s", d.serial}, args...),
+			Code:   res.Code,
+			Stderr: res.StderrString(),
+			Err:    ErrCommandFailed,
+		}
+	}
+	return nil
+}
+
+// EnablePackage enables a package, equivalent to `adb shell pm enable <pkg>`.
+func (d Device) EnablePackage(ctx context.Context, pkg string) error {
+	return d.execChecked(ctx, "shell", "pm", "enable", pkg)
+}
+
+// DisablePackage disables a package for the current user, equivalent to
+// `adb shell pm disable-user <pkg>`. Unlike `pm disable` this does not require
+// root.
+func (d Device) DisablePackage(ctx context.Context, pkg string) error {
+	return d.execChecked(ctx, "shell", "pm", "disable-user", pkg)
+}
+
+// ForegroundActivity returns the component of the resumed foreground activity
+// (for example "com.example/.MainActivity"), parsed from
+// `dumpsys activity activities` and falling back to the focused window in
+// `dumpsys window`. It returns "" when no activity is in the foreground, for
+// example while the screen is off.
+func (d Device) ForegroundActivity(ctx context.Context) (string, error) {
+	res, err := d.client.run(ctx, "-s", d.serial, "shell", "dumpsys", "activity", "activities")
+	if err != nil {
+		return "", err
+	}
+	if act := parseResumedActivity(res.StdoutString()); act != "" {
+		return act, nil
+	}
+	res, err = d.client.run(ctx, "-s", d.serial, "shell", "dumpsys", "window")
+	if err != nil {
+		return "", err
+	}
+	return parseFocusedWindow(res.StdoutString()), nil
+}
+
+// reResumedActivity matches the resumed-activity record in
+// `dumpsys activity activities` across releases: mResumedActivity (<= 9),
+// topResumedActivity and ResumedActivity (10+).
+var reResumedActivity = regexp.MustCompile(`(?:mResumedActivity|ResumedActivity|topResumedActivity)[:=]\s*ActivityRecord\{\S+ u\d+ ([^\s}]+)`)
+
+func parseResumedActivity(out string) string {
+	m := reResumedActivity.FindStringSubmatch(out)
+	if m == nil {
+		return ""
+	}
+	return m[1]
+}
+
+// reFocusedWindow matches the focused app in `dumpsys window`, either as an
+// ActivityRecord (mFocusedApp) or an application window (mCurrentFocus).
+// System windows such as the status bar have no "/" and are not matched.
+var reFocusedWindow = regexp.MustCompile(`(?:mFocusedApp=(?:AppWindowToken\{\S+ token=Token\{\S+ )?ActivityRecord\{\S+ u\d+ |mCurrentFocus=Window\{\S+ u\d+ )([^\s}]+/[^\s}]+)`)
+
+func parseFocusedWindow(out string) string {
+	m := reFocusedWindow.FindStringSubmatch(out)
+	if m == nil {
+		return ""
+	}
+	return m[1]
+}
@@ -0,0 +1,80 @@
+package adb
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestAppLifecycleArgs(t *testing.T) {
+	tests := []struct {
+		name   string
+		stdout string
+		call   func(Device) error
+		want   []string
+	}{
+		{name: "force-stop", call: func(d Device) error { return d.ForceStop(context.Background(), "com.example") }, want: []string{"-s", "S", "shell", "am", "force-stop", "com.example"}},
+		{name: "kill", call: func(d Device) error { return d.Kill(context.Background(), "com.example") }, want: []string{"-s", "S", "shell", "am", "kill", "com.example"}},
+		{name: "clear", stdout: "Success\n", call: func(d Device) error { return d.ClearData(context.Background(), "com.example") }, want: []string{"-s", "S", "shell", "pm", "clear", "com.example"}},
+		{name: "enable", stdout: "Package com.example new state: enabled\n", call: func(d Device) error { return d.EnablePackage(context.Background(), "com.example") }, want: []string{"-s", "S", "shell", "pm", "enable", "com.example"}},
+		{name: "disable", stdout: "Package com.example new state: disabled-user\n", call: func(d Device) error { return d.DisablePackage(context.Background(), "com.example") }, want: []string{"-s", "S", "shell", "pm", "disable-user", "com.example"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, argsFile := fakeADB(t, tt.stdout, "", 0)
+			if err := tt.call(c.Device("S")); err != nil {
+				t.Fatalf("%s error = %v", tt.name, err)
+			}
+			if got := readArgs(t, argsFile); !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("args = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClearData_Failed(t *testing.T) {
+	c, _ := fakeADB(t, "Failed\n", "", 0)
+	if err := c.Device("S").ClearData(context.Background(), "com.missing"); !errors.Is(err, ErrCommandFailed) {
+		t.Fatalf("ClearData() error = %v, want ErrCommandFailed", err)
+	}
+}
+
+func TestDisablePackage_Exception(t *testing.T) {
+	c, _ := fakeADB(t, "Error: java.lang.IllegalArgumentException: Unknown package: com.missing\n", "", 0)
+	if err := c.Device("S").DisablePackage(context.Background(), "com.missing"); !errors.Is(err, ErrCommandFailed) {
+		t.Fatalf("DisablePackage() error = %v, want ErrCommandFailed", err)
+	}
+}
+
+func TestForegroundActivity_FallsBackToWindow(t *testing.T) {
+	c, calls := fakeADBSeq(t,
+		fakeResponse{stdout: "ACTIVITY MANAGER ACTIVITIES (dumpsys activity activities)\n"},
+		fakeResponse{stdout: "  mCurrentFocus=Window{a1b2c3 u0 com.example/com.example.MainActivity}\n"},
+	)
+	got, err := c.Device("S").ForegroundActivity(context.Background())
+	if err != nil {
+		t.Fatalf("ForegroundActivity() error = %v", err)
+	}
+	if got != "com.example/com.example.MainActivity" {
+		t.Fatalf("ForegroundActivity() = %q", got)
+	}
+	if n := len(calls()); n != 2 {
+		t.Fatalf("ForegroundActivity() made %d calls, want 2", n)
+	}
+}
+
+func TestParseFocusedWindow(t *testing.T) {
+	tests := map[string]string{
+		"  mFocusedApp=ActivityRecord{5e6f u0 com.example/.Main t9}\n":                                                "com.example/.Main",
+		"  mFocusedApp=AppWindowToken{1 token=Token{2 ActivityRecord{3 u0 com.example/.Legacy t4}}}\n":                "com.example/.Legacy",
+		"  mCurrentFocus=Window{a1b2c3 u0 com.example/com.example.Main}\n":                                            "com.example/com.example.Main",
+		"  mCurrentFocus=Window{a1b2c3 u0 StatusBar}\n":                                                               "",
+		"  mCurrentFocus=Window{a1 u0 NotificationShade}\n  mFocusedApp=ActivityRecord{5e6f u0 com.example/.Main t9}": "com.example/.Main",
+	}
+	for in, want := range tests {
+		if got := parseFocusedWindow(in); got != want {
+			t.Fatalf("parseFocusedWindow(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
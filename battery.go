@@ -0,0 +1,235 @@
+package adb
+
+import (
+	"context"
+	"strconv"
+	"strings"
+)
+
+// BatteryStatus is the charging status reported by the battery service, from
+// android.os.BatteryManager.BATTERY_STATUS_*.
+type BatteryStatus int
+
+const (
+	// BatteryStatusUnknown is reported when the status cannot be determined.
+	BatteryStatusUnknown BatteryStatus = 1
+	// BatteryStatusCharging is reported while the battery is charging.
+	BatteryStatusCharging BatteryStatus = 2
+	// BatteryStatusDischarging is reported while running on battery.
+	BatteryStatusDischarging BatteryStatus = 3
+	// BatteryStatusNotCharging is reported when plugged in but not charging.
+	BatteryStatusNotCharging BatteryStatus = 4
+	// BatteryStatusFull is reported when plugged in and fully charged.
+	BatteryStatusFull BatteryStatus = 5
+)
+
+// String returns the status name, for example "charging".
+func (s BatteryStatus) String() string {
+	switch s {
+	case BatteryStatusCharging:
+		return "charging"
+	case BatteryStatusDischarging:
+		return "discharging"
+	case BatteryStatusNotCharging:
+		return "not-charging"
+	case BatteryStatusFull:
+		return "full"
+	default:
+		return "unknown"
+	}
+}
+
+// BatteryHealth is the battery health reported by the battery service, from
+// android.os.BatteryManager.BATTERY_HEALTH_*.
+type BatteryHealth int
+
+const (
+	// BatteryHealthUnknown is reported when the health cannot be determined.
+	BatteryHealthUnknown BatteryHealth = 1
+	// BatteryHealthGood is a healthy battery.
+	BatteryHealthGood BatteryHealth = 2
+	// BatteryHealthOverheat is an overheating battery.
+	BatteryHealthOverheat BatteryHealth = 3
+	// BatteryHealthDead is a dead battery.
+	BatteryHealthDead BatteryHealth = 4
+	// BatteryHealthOverVoltage is a battery over its rated voltage.
+	BatteryHealthOverVoltage BatteryHealth = 5
+	// BatteryHealthUnspecifiedFailure is a battery failure of unknown cause.
+	BatteryHealthUnspecifiedFailure BatteryHealth = 6
+	// BatteryHealthCold is a battery too cold to charge.
+	BatteryHealthCold BatteryHealth = 7
+)
+
+// String returns the health name, for example "good".
+func (h BatteryHealth) String() string {
+	switch h {
+	case BatteryHealthGood:
+		return "good"
+	case BatteryHealthOverheat:
+		return "overheat"
+	case BatteryHealthDead:
+		return "dead"
+	case BatteryHealthOverVoltage:
+		return "over-voltage"
+	case BatteryHealthUnspecifiedFailure:
+		return "unspecified-failure"
+	case BatteryHealthCold:
+		return "cold"
+	default:
+		return "unknown"
+	}
+}
+
+// PowerSource is a bitmask of the power sources a device is plugged into,
+// from android.os.BatteryManager.BATTERY_PLUGGED_*. Zero means on battery.
+type PowerSource int
+
+const (
+	// PowerAC is an AC charger.
+	PowerAC PowerSource = 1 << 0
+	// PowerUSB is a USB port.
+	PowerUSB PowerSource = 1 << 1
+	// PowerWireless is a wireless charger.
+	PowerWireless PowerSource = 1 << 2
+	// PowerDock is a dock (Android 14+).
+	PowerDock PowerSource = 1 << 3
+)
+
+// BatteryInfo is the battery state parsed from `dumpsys battery`.
+type BatteryInfo struct {
+	// Level is the charge level, out of Scale.
+	Level int
+	// Scale is the maximum Level, normally 100.
+	Scale  int
+	Status BatteryStatus
+	Health BatteryHealth
+	// Plugged is the set of power sources the device is drawing from.
+	Plugged PowerSource
+	// Present reports whether a battery is present.
+	Present bool
+	// Temperature is the battery temperature in degrees Celsius.
+	Temperature float64
+	// Voltage is the battery voltage in millivolts.
+	Voltage int
+	// Technology describes the battery chemistry, for example "Li-ion".
+	Technology string
+	// Simulated reports whether the values are overridden by
+	// `dumpsys battery set` or `unplug` rather than read from the hardware.
+	Simulated bool
+}
+
+// Percent returns the charge level as a percentage of Scale.
+func (b BatteryInfo) Percent() int {
+	if b.Scale <= 0 {
+		return b.Level
+	}
+	return b.Level * 100 / b.Scale
+}
+
+// Battery returns the battery state, equivalent to `adb shell dumpsys battery`.
+func (d Device) Battery(ctx context.Context) (BatteryInfo, error) {
+	res, err := d.client.run(ctx, "-s", d.serial, "shell", "dumpsys", "battery")
+	if err != nil {
+		return BatteryInfo{}, err
+	}
+	return parseBattery(res.StdoutString()), nil
+}
+
+func parseBattery(out string) BatteryInfo {
+	var b BatteryInfo
+	for line := range strings.SplitSeq(out, "\n") {
+		line = strings.TrimSpace(line)
+		if strings.Contains(line, "UPDATES STOPPED") {
+			b.Simulated = true
+			continue
+		}
+		key, value, ok := strings.Cut(line, ": ")
+		if !ok {
+			continue
+		}
+		n, _ := strconv.Atoi(value)
+		switch key {
+		case "AC powered":
+			b.Plugged |= powered(value, PowerAC)
+		case "USB powered":
+			b.Plugged |= powered(value, PowerUSB)
+		case "Wireless powered":
+			b.Plugged |= powered(value, PowerWireless)
+		case "Dock powered":
+			b.Plugged |= powered(value, PowerDock)
+		case "status":
+			b.Status = BatteryStatus(n)
+		case "health":
+			b.Health = BatteryHealth(n)
+		case "present":
+			b.Present = value == "true"
+		case "level":
+			b.Level = n
+		case "scale":
+			b.Scale = n
+		case "voltage":
+			b.Voltage = n
+		case "temperature":
+			// Reported in tenths of a degree.
+			b.Temperature = float64(n) / 10
+		case "technology":
+			b.Technology = value
+		}
+	}
+	return b
+}
+
+func powered(value string, src PowerSource) PowerSource {
+	if value == "true" {
+		return src
+	}
+	return 0
+}
+
+// SetBatteryLevel simulates a charge level, equivalent to
+// `adb shell dumpsys battery set level <level>`. The simulation lasts until
+// [Device.ResetBattery].
+func (d Device) SetBatteryLevel(ctx context.Context, level int) error {
+	return d.setBattery(ctx, "level", level)
+}
+
+// SetBatteryStatus simulates a charging status, equivalent to
+// `adb shell dumpsys battery set status <status>`.
+func (d Device) SetBatteryStatus(ctx context.Context, status BatteryStatus) error {
+	return d.setBattery(ctx, "status", int(status))
+}
+
+// SetPowerSource simulates being plugged into the given power sources,
+// equivalent to `adb shell dumpsys battery set ac|usb|wireless <0|1>`. Pass 0
+// to simulate running on battery. [PowerDock] is not settable and is ignored.
+func (d Device) SetPowerSource(ctx context.Context, src PowerSource) error {
+	for _, s := range []struct {
+		name string
+		src  PowerSource
+	}{{"ac", PowerAC}, {"usb", PowerUSB}, {"wireless", PowerWireless}} {
+		on := 0
+		if src&s.src != 0 {
+			on = 1
+		}
+		if err := d.setBattery(ctx, s.name, on); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (d Device) setBattery(ctx context.Context, key string, value int) error {
+	return d.exec(ctx, "shell", "dumpsys", "battery", "set", key, strconv.Itoa(value))
+}
+
+// UnplugBattery simulates disconnecting every power source, equivalent to
+// `adb shell dumpsys battery unplug`.
+func (d Device) UnplugBattery(ctx context.Context) error {
+	return d.exec(ctx, "shell", "dumpsys", "battery", "unplug")
+}
+
+// ResetBattery ends any battery simulation and resumes reporting the hardware
+// state, equivalent to `adb shell dumpsys battery reset`.
+func (d Device) ResetBattery(ctx context.Context) error {
+	return d.exec(ctx, "shell", "dumpsys", "battery", "reset")
+}
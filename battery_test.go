@@ -0,0 +1,99 @@
+package adb
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+const batteryDump = `Current Battery Service state:
+  (UPDATES STOPPED -- use 'reset' to restart)
+  AC powered: false
+  USB powered: true
+  Wireless powered: false
+  Dock powered: false
+  Max charging current: 500000
+  Max charging voltage: 5000000
+  Charge counter: 2774000
+  status: 2
+  health: 2
+  present: true
+  level: 15
+  scale: 100
+  voltage: 3812
+  temperature: 287
+  technology: Li-ion
+`
+
+func TestBattery(t *testing.T) {
+	c, argsFile := fakeADB(t, batteryDump, "", 0)
+	got, err := c.Device("S").Battery(context.Background())
+	if err != nil {
+		t.Fatalf("Battery() error = %v", err)
+	}
+	want := BatteryInfo{
+		Level:       15,
+		Scale:       100,
+		Status:      BatteryStatusCharging,
+		Health:      BatteryHealthGood,
+		Plugged:     PowerUSB,
+		Present:     true,
+		Temperature: 28.7,
+		Voltage:     3812,
+		Technology:  "Li-ion",
+		Simulated:   true,
+	}
+	if got != want {
+		t.Fatalf("Battery() = %+v, want %+v", got, want)
+	}
+	if got.Percent() != 15 || got.Status.String() != "charging" || got.Health.String() != "good" {
+		t.Fatalf("Battery() helpers = %d %s %s", got.Percent(), got.Status, got.Health)
+	}
+	wantArgs := []string{"-s", "S", "shell", "dumpsys", "battery"}
+	if args := readArgs(t, argsFile); !reflect.DeepEqual(args, wantArgs) {
+		t.Fatalf("Battery() args = %v, want %v", args, wantArgs)
+	}
+}
+
+func TestBatteryControls(t *testing.T) {
+	tests := []struct {
+		name string
+		call func(Device) error
+		want []string
+	}{
+		{"level", func(d Device) error { return d.SetBatteryLevel(context.Background(), 5) }, []string{"set", "level", "5"}},
+		{"status", func(d Device) error {
+			return d.SetBatteryStatus(context.Background(), BatteryStatusDischarging)
+		}, []string{"set", "status", "3"}},
+		{"unplug", func(d Device) error { return d.UnplugBattery(context.Background()) }, []string{"unplug"}},
+		{"reset", func(d Device) error { return d.ResetBattery(context.Background()) }, []string{"reset"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, argsFile := fakeADB(t, "", "", 0)
+			if err := tt.call(c.Device("S")); err != nil {
+				t.Fatalf("%s error = %v", tt.name, err)
+			}
+			want := append([]string{"-s", "S", "shell", "dumpsys", "battery"}, tt.want...)
+			if got := readArgs(t, argsFile); !reflect.DeepEqual(got, want) {
+				t.Fatalf("args = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestSetPowerSource(t *testing.T) {
+	c, calls := fakeADBSeq(t, fakeResponse{})
+	if err := c.Device("S").SetPowerSource(context.Background(), PowerAC|PowerWireless); err != nil {
+		t.Fatalf("SetPowerSource() error = %v", err)
+	}
+	prefix := []string{"-s", "S", "shell", "dumpsys", "battery", "set"}
+	want := [][]string{
+		append(prefix[:len(prefix):len(prefix)], "ac", "1"),
+		append(prefix[:len(prefix):len(prefix)], "usb", "0"),
+		append(prefix[:len(prefix):len(prefix)], "wireless", "1"),
+	}
+	if got := calls(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("SetPowerSource() calls = %v, want %v", got, want)
+	}
+}
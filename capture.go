@@ -17,13 +17,15 @@ type Resolution struct {
 	Height int
 }
 
-// Sequence is a series of taps, swipes, and pauses that can be replayed
+// Sequence is a series of taps, swipes, gestures, and pauses that can be replayed
 // against a device. Create one by recording ([Device.Record]), building it
 // programmatically ([NewSequence]), or restoring JSON ([ParseSequence] /
 // [Sequence.UnmarshalJSON]).
 type Sequence struct {
 	resolution Resolution
 	events     []event
+	display    int
+	hasDisplay bool
 }
 
 // eventKind identifies the kind of a recorded event.
@@ -32,6 +34,7 @@ type eventKind int
 const (
 	kindSwipe eventKind = iota
 	kindSleep
+	kindGesture
 )
 
 // event is a single replayable action. Taps are represented as zero-distance
@@ -43,6 +46,7 @@ type event struct {
 	Duration time.Duration
 	Start    time.Time
 	End      time.Time
+	Gesture  *Gesture
 }
 
 // EventKind identifies the kind of an [Event].
@@ -53,16 +57,21 @@ const (
 	SwipeEvent EventKind = iota
 	// SleepEvent is a pause between actions.
 	SleepEvent
+	// GestureEvent is a multi-pointer or held [Gesture].
+	GestureEvent
 )
 
 // Event is a single replayable action in a [Sequence] as seen through the
 // public API. Obtain events via [Sequence.Events] and build them with
-// [NewTap], [NewSwipe], and [NewSleep].
+// [NewTap], [NewSwipe], [NewSleep], and [NewGesture].
 type Event struct {
 	Kind     EventKind
 	X1, Y1   int
 	X2, Y2   int
 	Duration time.Duration
+	// Gesture is the gesture performed by a GestureEvent, and nil for every
+	// other kind.
+	Gesture *Gesture
 }
 
 // NewTap returns an Event that taps the point (x, y).
@@ -80,6 +89,11 @@ func NewSleep(d time.Duration) Event {
 	return Event{Kind: SleepEvent, Duration: d}
 }
 
+// NewGesture returns an Event that performs g (see [Device.Gesture]).
+func NewGesture(g Gesture) Event {
+	return Event{Kind: GestureEvent, Duration: g.length(), Gesture: g.clone()}
+}
+
 // NewSequence builds a Sequence from the given events. The resolution is
 // advisory metadata describing the screen the events were authored for.
 func NewSequence(resolution Resolution, events ...Event) Sequence {
@@ -91,8 +105,11 @@ func NewSequence(resolution Resolution, events ...Event) Sequence {
 }
 
 func (e Event) toInternal() event {
-	if e.Kind == SleepEvent {
+	switch e.Kind {
+	case SleepEvent:
 		return event{Kind: kindSleep, Duration: e.Duration}
+	case GestureEvent:
+		return event{Kind: kindGesture, Gesture: e.Gesture.clone()}
 	}
 	return event{
 		Kind: kindSwipe,
@@ -103,8 +120,11 @@ func (e Event) toInternal() event {
 }
 
 func (e event) toPublic() Event {
-	if e.Kind == kindSleep {
+	switch e.Kind {
+	case kindSleep:
 		return Event{Kind: SleepEvent, Duration: e.Duration}
+	case kindGesture:
+		return Event{Kind: GestureEvent, Duration: e.length(), Gesture: e.Gesture.clone()}
 	}
 	return Event{
 		Kind: SwipeEvent,
@@ -125,6 +145,17 @@ func (s Sequence) Events() []Event {
 // Resolution returns the screen resolution associated with the sequence.
 func (s Sequence) Resolution() Resolution { return s.resolution }
 
+// OnDisplay returns a copy of the sequence that [Device.Replay] plays on the
+// display with the given id (see [Device.OnDisplay]).
+func (s Sequence) OnDisplay(id int) Sequence {
+	s.display, s.hasDisplay = id, true
+	return s
+}
+
+// Display returns the display set with [Sequence.OnDisplay]. The boolean is
+// false when the sequence replays on the device's own target display.
+func (s Sequence) Display() (int, bool) { return s.display, s.hasDisplay }
+
 // Len returns the number of recorded events.
 func (s Sequence) Len() int { return len(s.events) }
 
@@ -144,6 +175,11 @@ func (e event) length() time.Duration {
 	switch e.Kind {
 	case kindSleep:
 		return e.Duration
+	case kindGesture:
+		if e.Gesture == nil {
+			return 0
+		}
+		return e.Gesture.length()
 	default:
 		return e.End.Sub(e.Start)
 	}
@@ -155,7 +191,8 @@ func (s Sequence) ShortenSleeps(factor int) Sequence {
 	if factor <= 0 {
 		return s
 	}
-	out := Sequence{resolution: s.resolution, events: make([]event, len(s.events))}
+	out := s
+	out.events = make([]event, len(s.events))
 	copy(out.events, s.events)
 	for i := range out.events {
 		if out.events[i].Kind == kindSleep {
@@ -168,6 +205,7 @@ func (s Sequence) ShortenSleeps(factor int) Sequence {
 // sequenceJSON is the on-disk representation of a Sequence.
 type sequenceJSON struct {
 	Resolution Resolution  `json:"resolution"`
+	Display    *int        `json:"display,omitempty"`
 	Events     []eventJSON `json:"events"`
 }
 
@@ -178,17 +216,27 @@ type eventJSON struct {
 	X2       int           `json:"x2,omitempty"`
 	Y2       int           `json:"y2,omitempty"`
 	Duration time.Duration `json:"duration,omitempty"`
+	Hold     time.Duration `json:"hold,omitempty"`
+	Pointers [][]Point     `json:"pointers,omitempty"`
 }
 
 // MarshalJSON implements [json.Marshaler].
 func (s Sequence) MarshalJSON() ([]byte, error) {
 	out := sequenceJSON{Resolution: s.resolution, Events: make([]eventJSON, len(s.events))}
+	if s.hasDisplay {
+		out.Display = &s.display
+	}
 	for i, e := range s.events {
 		ej := eventJSON{X1: e.X1, Y1: e.Y1, X2: e.X2, Y2: e.Y2}
 		switch e.Kind {
 		case kindSleep:
 			ej.Kind = "sleep"
 			ej.Duration = e.Duration
+		case kindGesture:
+			ej.Kind = "gesture"
+			if e.Gesture != nil {
+				ej.Duration, ej.Hold, ej.Pointers = e.Gesture.Duration, e.Gesture.Hold, e.Gesture.Pointers
+			}
 		default:
 			ej.Kind = "swipe"
 			ej.Duration = e.End.Sub(e.Start)
@@ -206,11 +254,17 @@ func ParseSequence(data []byte) (Sequence, error) {
 		return Sequence{}, err
 	}
 	s := Sequence{resolution: in.Resolution, events: make([]event, 0, len(in.Events))}
+	if in.Display != nil {
+		s.display, s.hasDisplay = *in.Display, true
+	}
 	for _, ej := range in.Events {
 		e := event{X1: ej.X1, Y1: ej.Y1, X2: ej.X2, Y2: ej.Y2, Duration: ej.Duration}
-		if ej.Kind == "sleep" {
+		switch ej.Kind {
+		case "sleep":
 			e.Kind = kindSleep
-		} else {
+		case "gesture":
+			e = event{Kind: kindGesture, Gesture: &Gesture{Pointers: ej.Pointers, Hold: ej.Hold, Duration: ej.Duration}}
+		default:
 			e.Kind = kindSwipe
 			e.End = time.Time{}.Add(ej.Duration)
 		}
@@ -257,8 +311,12 @@ func (d Device) Record(ctx context.Context) (Sequence, error) {
 }
 
 // Replay plays every event in the sequence against the device in order,
-// stopping on the first error. Pauses honor ctx cancellation.
+// stopping on the first error. Pauses honor ctx cancellation. A display set
+// with [Sequence.OnDisplay] overrides the device's own target display.
 func (d Device) Replay(ctx context.Context, s Sequence) error {
+	if s.hasDisplay {
+		d = d.OnDisplay(s.display)
+	}
 	for _, e := range s.events {
 		if err := e.play(ctx, d); err != nil {
 			return err
@@ -278,6 +336,13 @@ func (e event) play(ctx context.Context, d Device) error {
 			return nil
 		}
 	}
+	if e.Kind == kindGesture {
+		var g Gesture
+		if e.Gesture != nil {
+			g = *e.Gesture
+		}
+		return d.Gesture(ctx, g)
+	}
 	// Only a zero-distance, zero-duration contact is an instantaneous tap.
 	// A stationary contact with a real duration is a press/long-press and must
 	// preserve its duration, which `input swipe` with equal endpoints does
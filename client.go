@@ -4,7 +4,10 @@ import (
 	"bytes"
 	"context"
 	"errors"
+	"net"
+	"os"
 	"os/exec"
+	"regexp"
 	"strings"
 	"time"
 )
@@ -14,6 +17,7 @@ import (
 type Client struct {
 	binary         string
 	defaultTimeout time.Duration
+	serverAddr     string
 }
 
 // Option configures a [Client].
@@ -31,6 +35,12 @@ func WithDefaultTimeout(d time.Duration) Option {
 	return func(c *Client) { c.defaultTimeout = d }
 }
 
+// WithServerAddr sets the address of the adb server used by [Device.Dial].
+// It defaults to 127.0.0.1 on the port in $ANDROID_ADB_SERVER_PORT, or 5037.
+func WithServerAddr(addr string) Option {
+	return func(c *Client) { c.serverAddr = addr }
+}
+
 // New creates a Client, resolving the adb binary from PATH unless overridden
 // with [WithBinary]. It returns [ErrNotInstalled] if adb cannot be found.
 func New(opts ...Option) (*Client, error) {
@@ -45,6 +55,13 @@ func New(opts ...Option) (*Client, error) {
 		}
 		c.binary = path
 	}
+	if c.serverAddr == "" {
+		port := os.Getenv("ANDROID_ADB_SERVER_PORT")
+		if port == "" {
+			port = "5037"
+		}
+		c.serverAddr = net.JoinHostPort("127.0.0.1", port)
+	}
 	return c, nil
 }
 
@@ -155,12 +172,16 @@ func classify(res Result, runErr error) error {
 	return runErr
 }
 
+// reDeviceNotFound matches the "device '<serial>' not found" error adb reports
+// when addressing a serial it does not know.
+var reDeviceNotFound = regexp.MustCompile(`device '[^']*' not found`)
+
 // filterStderr maps known adb stderr messages to typed sentinel errors.
 func filterStderr(stderr string) error {
 	switch {
 	case stderr == "":
 		return nil
-	case strings.Contains(stderr, "device not found"):
+	case strings.Contains(stderr, "device not found"), reDeviceNotFound.MatchString(stderr):
 		return ErrDeviceNotFound
 	case strings.Contains(stderr, "device offline"):
 		return ErrDeviceOffline
@@ -181,10 +202,17 @@ func filterStderr(stderr string) error {
 // a blanket substring search so that data echoed back by adb — such as a
 // component name containing "Exception" or an APK path containing "Error" —
 // does not trigger a false positive.
+//
+// Package-manager install failures ("Failure [INSTALL_FAILED_*: detail]",
+// possibly prefixed with "adb: failed to install <apk>: ") are returned as an
+// *[InstallError], which also matches [ErrCommandFailed].
 func outputFailure(streams ...string) error {
 	for _, s := range streams {
 		for line := range strings.SplitSeq(s, "\n") {
 			line = strings.TrimSpace(line)
+			if err := installFailure(line); err != nil {
+				return err
+			}
 			switch {
 			case strings.HasPrefix(line, "Failure"),
 				strings.HasPrefix(line, "Error:"),
@@ -197,3 +225,18 @@ func outputFailure(streams ...string) error {
 	}
 	return nil
 }
+
+// reInstallFailure matches a package-manager failure code and its optional
+// detail at the start of a line, after adb's optional "failed to install"
+// prefix, so echoed output that merely mentions a failure does not match. The
+// detail is greedy so it may itself contain brackets.
+var reInstallFailure = regexp.MustCompile(`^(?:adb: failed to install .*?: )?Failure \[(INSTALL_[A-Z0-9_]+)(?::\s*(.*))?\]`)
+
+// installFailure returns an *InstallError if line reports an install failure.
+func installFailure(line string) error {
+	m := reInstallFailure.FindStringSubmatch(line)
+	if m == nil {
+		return nil
+	}
+	return &InstallError{Reason: InstallFailureReason(m[1]), Detail: strings.TrimSpace(m[2])}
+}
@@ -0,0 +1,93 @@
+package adb
+
+import (
+	"context"
+	"regexp"
+	"strings"
+)
+
+// Clipboard returns the text on the device clipboard, equivalent to
+// `adb shell cmd clipboard get-primary-clip`. It returns "" when the
+// clipboard is empty or holds no text.
+//
+// On releases without the clipboard shell command, including those before
+// Android 7 that lack `cmd` entirely, it falls back to the Clipper app (https://github.com/majido/clipper) via a clipper.get
+// broadcast, returning [ErrClipboardUnavailable] if Clipper does not answer.
+// Android 10+ only lets Clipper read the clipboard while it is in the
+// foreground.
+func (d Device) Clipboard(ctx context.Context) (string, error) {
+	args := []string{"cmd", "clipboard", "get-primary-clip"}
+	res, err := d.Shell(ctx, args[0], args[1:]...)
+	if err != nil {
+		return "", err
+	}
+	if !cmdUnsupported(res) {
+		if err := d.cmdFailure(res, args...); err != nil {
+			return "", err
+		}
+		return parseClip(res.StdoutString()), nil
+	}
+	res, err = d.runChecked(ctx, Intent{Action: "clipper.get"}.amArgs("broadcast")...)
+	if err != nil {
+		return "", err
+	}
+	m := reClipperData.FindStringSubmatch(res.StdoutString())
+	if m == nil {
+		if strings.Contains(res.StdoutString(), "result=-1") {
+			return "", nil
+		}
+		return "", ErrClipboardUnavailable
+	}
+	return m[1], nil
+}
+
+// reClipData matches the text item of a ClipData description such as
+// "ClipData { text/plain {T:hello} }".
+var reClipData = regexp.MustCompile(`(?s)^ClipData\s*\{.*?\{T:(.*)\}\s*\}\s*$`)
+
+// reClipperData matches the clipboard text returned by Clipper's broadcast.
+var reClipperData = regexp.MustCompile(`(?s)result=-1, data="(.*)"\s*$`)
+
+func parseClip(out string) string {
+	out = strings.TrimSuffix(out, "\n")
+	if out == "null" {
+		return ""
+	}
+	if m := reClipData.FindStringSubmatch(out); m != nil {
+		return m[1]
+	}
+	return out
+}
+
+// SetClipboard puts text on the device clipboard, equivalent to
+// `adb shell cmd clipboard set-primary-clip <text>`, falling back to the
+// Clipper app's clipper.set broadcast on releases without the clipboard
+// shell command. See [Device.Clipboard] for the fallback's requirements.
+func (d Device) SetClipboard(ctx context.Context, text string) error {
+	args := []string{"cmd", "clipboard", "set-primary-clip", shellQuote(text)}
+	res, err := d.Shell(ctx, args[0], args[1:]...)
+	if err != nil {
+		return err
+	}
+	if !cmdUnsupported(res) {
+		return d.cmdFailure(res, args...)
+	}
+	res, err = d.runChecked(ctx, Intent{Action: "clipper.set"}.WithString("text", text).amArgs("broadcast")...)
+	if err != nil {
+		return err
+	}
+	// Clipper reports success with RESULT_OK (-1); 0 means nothing received
+	// the broadcast.
+	if !strings.Contains(res.StdoutString(), "result=-1") {
+		return ErrClipboardUnavailable
+	}
+	return nil
+}
+
+// pasteText enters text by putting it on the clipboard and pressing paste.
+func (d Device) pasteText(ctx context.Context, text string) error {
+	if err := d.SetClipboard(ctx, text); err != nil {
+		return err
+	}
+	return d.KeyEvent(ctx, KeycodePaste)
+}
@@ -0,0 +1,115 @@
+package adb
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestClipboard(t *testing.T) {
+	tests := []struct {
+		out, want string
+	}{
+		{"hello world\n", "hello world"},
+		{"ClipData { text/plain {T:multi\nline} }\n", "multi\nline"},
+		{"null\n", ""},
+	}
+	for _, tt := range tests {
+		c, argsFile := fakeADB(t, tt.out, "", 0)
+		got, err := c.Device("S").Clipboard(context.Background())
+		if err != nil || got != tt.want {
+			t.Fatalf("Clipboard() = %q, %v; want %q", got, err, tt.want)
+		}
+		if args, want := readArgs(t, argsFile), []string{"-s", "S", "shell", "cmd", "clipboard", "get-primary-clip"}; !reflect.DeepEqual(args, want) {
+			t.Fatalf("Clipboard() args = %v, want %v", args, want)
+		}
+	}
+}
+
+func TestClipboard_ClipperFallback(t *testing.T) {
+	c, calls := fakeADBSeq(t,
+		fakeResponse{stdout: "Unknown command: get-primary-clip\n", code: 255},
+		fakeResponse{stdout: "Broadcasting: Intent { act=clipper.get flg=0x400000 }\nBroadcast completed: result=-1, data=\"copied\"\n"},
+	)
+	got, err := c.Device("S").Clipboard(context.Background())
+	if err != nil || got != "copied" {
+		t.Fatalf("Clipboard() = %q, %v; want copied", got, err)
+	}
+	if args := calls()[1]; !reflect.DeepEqual(args, []string{"-s", "S", "shell", "am", "broadcast", "-a", "clipper.get"}) {
+		t.Fatalf("fallback args = %v", args)
+	}
+
+	c, _ = fakeADBSeq(t,
+		fakeResponse{stdout: "Can't find service: clipboard\n"},
+		fakeResponse{stdout: "Broadcasting: Intent { act=clipper.get }\nBroadcast completed: result=0\n"},
+	)
+	if _, err := c.Device("S").Clipboard(context.Background()); !errors.Is(err, ErrClipboardUnavailable) {
+		t.Fatalf("Clipboard() error = %v, want ErrClipboardUnavailable", err)
+	}
+}
+
+func TestClipboard_PreNougatFallback(t *testing.T) {
+	// Before Android 7 a missing cmd binary still exits 0.
+	c, calls := fakeADBSeq(t,
+		fakeResponse{stdout: "/system/bin/sh: cmd: not found\n"},
+		fakeResponse{stdout: "Broadcasting: Intent { act=clipper.get }\nBroadcast completed: result=-1, data=\"old\"\n"},
+	)
+	got, err := c.Device("S").Clipboard(context.Background())
+	if err != nil || got != "old" {
+		t.Fatalf("Clipboard() = %q, %v; want old", got, err)
+	}
+	if n := len(calls()); n != 2 {
+		t.Fatalf("Clipboard() calls = %d, want 2", n)
+	}
+
+	c, calls = fakeADBSeq(t,
+		fakeResponse{stdout: "/system/bin/sh: cmd: not found\n"},
+		fakeResponse{stdout: "Broadcast completed: result=-1\n"},
+	)
+	if err := c.Device("S").SetClipboard(context.Background(), "x"); err != nil {
+		t.Fatalf("SetClipboard() error = %v", err)
+	}
+	if n := len(calls()); n != 2 {
+		t.Fatalf("SetClipboard() calls = %d, want 2", n)
+	}
+}
+
+func TestSetClipboard(t *testing.T) {
+	c, argsFile := fakeADB(t, "", "", 0)
+	if err := c.Device("S").SetClipboard(context.Background(), "it's here"); err != nil {
+		t.Fatalf("SetClipboard() error = %v", err)
+	}
+	want := []string{"-s", "S", "shell", "cmd", "clipboard", "set-primary-clip", `'it'\''s here'`}
+	if got := readArgs(t, argsFile); !reflect.DeepEqual(got, want) {
+		t.Fatalf("SetClipboard() args = %v, want %v", got, want)
+	}
+}
+
+func TestSetClipboard_ClipperFallback(t *testing.T) {
+	c, calls := fakeADBSeq(t,
+		fakeResponse{stdout: "Unknown command: set-primary-clip\n", code: 255},
+		fakeResponse{stdout: "Broadcast completed: result=0\n"},
+	)
+	if err := c.Device("S").SetClipboard(context.Background(), "x y"); !errors.Is(err, ErrClipboardUnavailable) {
+		t.Fatalf("SetClipboard() error = %v, want ErrClipboardUnavailable", err)
+	}
+	want := []string{"-s", "S", "shell", "am", "broadcast", "-a", "clipper.set", "--es", "text", "'x y'"}
+	if got := calls()[1]; !reflect.DeepEqual(got, want) {
+		t.Fatalf("fallback args = %v, want %v", got, want)
+	}
+}
+
+func TestTypeText_ClipboardFallback(t *testing.T) {
+	c, calls := fakeADBSeq(t, fakeResponse{})
+	if err := c.Device("S").TypeText(context.Background(), "naïve", TextOptions{Fallback: UnicodeClipboard}); err != nil {
+		t.Fatalf("TypeText() error = %v", err)
+	}
+	want := [][]string{
+		{"-s", "S", "shell", "cmd", "clipboard", "set-primary-clip", "'naïve'"},
+		{"-s", "S", "shell", "input", "keyevent", "279"},
+	}
+	if got := calls(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("TypeText() calls = %q, want %q", got, want)
+	}
+}
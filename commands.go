@@ -2,6 +2,7 @@ package adb
 
 import (
 	"context"
+	"errors"
 	"os"
 	"regexp"
 	"strconv"
@@ -36,15 +37,78 @@ func (d Device) exec(ctx context.Context, args ...string) error {
 // (see outputFailure). Use it for subcommands — install, pm, am — that are
 // known to do this; general commands should use exec.
 func (d Device) execChecked(ctx context.Context, args ...string) error {
+	_, err := d.runChecked(ctx, args...)
+	return err
+}
+
+// runChecked is execChecked for callers that also need the command's output.
+func (d Device) runChecked(ctx context.Context, args ...string) (Result, error) {
 	full := append([]string{"-s", d.serial}, args...)
 	res, err := d.client.run(ctx, full...)
 	if err != nil {
-		return err
+		// Newer adb versions exit non-zero for failed installs; surface the
+		// typed failure reported in the output rather than a bare
+		// ErrCommandFailed.
+		if cmdErr, ok := errors.AsType[*CommandError](err); ok && errors.Is(cmdErr.Err, ErrCommandFailed) {
+			if cause := outputFailure(res.StdoutString(), res.StderrString()); cause != nil {
+				cmdErr.Err = cause
+			}
+		}
+		return res, err
 	}
 	if cause := outputFailure(res.StdoutString(), res.StderrString()); cause != nil {
-		return &CommandError{Args: full, Code: res.Code, Stderr: res.StderrString(), Err: cause}
+		return res, &CommandError{Args: full, Code: res.Code, Stderr: res.StderrString(), Err: cause}
+	}
+	return res, nil
+}
+
+// shellQuote quotes s so the device-side shell passes it through as a single
+// literal word. Strings made only of characters the shell treats literally are
+// returned unchanged, keeping simple arguments (package and component names,
+// numbers) readable in logs and [CommandError.Args].
+func shellQuote(s string) string {
+	if s != "" && strings.IndexFunc(s, func(r rune) bool {
+		return (r < 'a' || r > 'z') && (r < 'A' || r > 'Z') && (r < '0' || r > '9') &&
+			!strings.ContainsRune("@%+=:,./_-", r)
+	}) < 0 {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// cmdUnsupported reports whether a `cmd <service> <command>` invocation failed
+// because the release does not implement the service's shell command (or
+// lacks `cmd` altogether), so the caller should fall back to an older
+// mechanism. Before Android 7 the adb shell does not propagate exit codes, so
+// a missing `cmd` binary shows up only as "cmd: not found" on stdout with
+// exit code 0. Other failures are left to [Device.cmdFailure].
+func cmdUnsupported(res Result) bool {
+	out := res.StdoutString() + res.StderrString()
+	return res.Code == 127 || strings.Contains(out, "cmd: not found") ||
+		strings.Contains(out, "inaccessible or not found") ||
+		strings.Contains(out, "Unknown command") ||
+		strings.Contains(out, "No shell command implementation") ||
+		strings.Contains(out, "Can't find service")
+}
+
+// cmdFailure returns a *CommandError for a `cmd` invocation, run with
+// [Device.Shell], that exited non-zero or reported a failure (such as a
+// SecurityException) on stderr, and nil if it succeeded. Stdout is not
+// scanned because it may echo data such as clipboard text.
+func (d Device) cmdFailure(res Result, args ...string) error {
+	cause := outputFailure(res.StderrString())
+	if cause == nil && res.Code != 0 {
+		cause = ErrCommandFailed
+	}
+	if cause == nil {
+		return nil
+	}
+	return &CommandError{
+		Args:   append([]string{"-s", d.serial, "shell"}, args...),
+		Code:   res.Code,
+		Stderr: res.StderrString(),
+		Err:    cause,
 	}
-	return nil
 }
 
 // Push copies a local file to the device, equivalent to `adb push`.
@@ -111,7 +175,7 @@ func (d Device) TCPIP(ctx context.Context, port uint16) error {
 // WaitForDevice blocks until the device is available or ctx is cancelled,
 // equivalent to `adb wait-for-device`.
 func (d Device) WaitForDevice(ctx context.Context) error {
-	return d.exec(ctx, "wait-for-device")
+	return d.WaitFor(ctx, UnknownTransport, WaitDevice)
 }
 
 // State returns the device's connection state (for example "device",
@@ -161,17 +225,10 @@ func (d Device) RemoveAllReverses(ctx context.Context) error {
 }
 
 // Install pushes and installs an APK, equivalent to `adb install`. When
-// replace is true the app is reinstalled keeping its data (`-r`).
+// replace is true the app is reinstalled keeping its data (`-r`). Use
+// [Device.InstallWithOptions] for the other install flags.
 func (d Device) Install(ctx context.Context, apkPath string, replace bool) error {
-	if _, err := os.Stat(apkPath); err != nil {
-		return err
-	}
-	args := []string{"install"}
-	if replace {
-		args = append(args, "-r")
-	}
-	args = append(args, apkPath)
-	return d.execChecked(ctx, args...)
+	return d.InstallWithOptions(ctx, apkPath, InstallOptions{Replace: replace})
 }
 
 // Uninstall removes an installed package, equivalent to `adb uninstall`.
@@ -210,13 +267,13 @@ func (d Device) Screencap(ctx context.Context, dest string) error {
 
 // Tap taps the screen at (x, y), equivalent to `adb shell input tap`.
 func (d Device) Tap(ctx context.Context, x, y int) error {
-	return d.exec(ctx, "shell", "input", "tap", strconv.Itoa(x), strconv.Itoa(y))
+	return d.input(ctx, "tap", strconv.Itoa(x), strconv.Itoa(y))
 }
 
 // Swipe swipes from (x1, y1) to (x2, y2) over duration, equivalent to
 // `adb shell input swipe`.
 func (d Device) Swipe(ctx context.Context, x1, y1, x2, y2 int, duration time.Duration) error {
-	return d.exec(ctx, "shell", "input", "swipe",
+	return d.input(ctx, "swipe",
 		strconv.Itoa(x1), strconv.Itoa(y1),
 		strconv.Itoa(x2), strconv.Itoa(y2),
 		strconv.FormatInt(duration.Milliseconds(), 10),
@@ -228,28 +285,12 @@ func (d Device) LongPress(ctx context.Context, x, y int) error {
 	return d.Swipe(ctx, x, y, x, y, 250*time.Millisecond)
 }
 
-// KeyEvent sends a key event, equivalent to `adb shell input keyevent`. The
-// keycode may be numeric ("3") or a KEYCODE_ constant ("KEYCODE_HOME").
-func (d Device) KeyEvent(ctx context.Context, keycode string) error {
-	return d.exec(ctx, "shell", "input", "keyevent", keycode)
-}
-
-// GoHome presses the home button.
-func (d Device) GoHome(ctx context.Context) error { return d.KeyEvent(ctx, "KEYCODE_HOME") }
-
-// GoBack presses the back button.
-func (d Device) GoBack(ctx context.Context) error { return d.KeyEvent(ctx, "KEYCODE_BACK") }
-
-// SwitchApp opens the app switcher. You probably want to call this twice.
-func (d Device) SwitchApp(ctx context.Context) error { return d.KeyEvent(ctx, "KEYCODE_APP_SWITCH") }
-
-// InputText types text on the device, equivalent to `adb shell input text`.
-//
-// Spaces are encoded as %s so multi-word strings are typed intact. A literal
-// "%" is not escaped and may be misinterpreted; other shell-special characters
-// are not escaped either.
+// InputText types text on the device with `adb shell input text`. It is
+// [Device.TypeText] with default options: special characters are escaped,
+// long text is chunked, and text `input text` cannot type (such as non-ASCII
+// characters) fails with [ErrUntypeableText].
 func (d Device) InputText(ctx context.Context, text string) error {
-	return d.exec(ctx, "shell", "input", "text", strings.ReplaceAll(text, " ", "%s"))
+	return d.TypeText(ctx, text, TextOptions{})
 }
 
 // GetProp returns a device system property, equivalent to
@@ -268,26 +309,6 @@ func (d Device) SetProp(ctx context.Context, prop, value string) error {
 	return d.exec(ctx, "shell", "setprop", prop, value)
 }
 
-// ListPackages returns the installed package names, equivalent to
-// `adb shell pm list packages`.
-func (d Device) ListPackages(ctx context.Context) ([]string, error) {
-	res, err := d.client.run(ctx, "-s", d.serial, "shell", "pm", "list", "packages")
-	if err != nil {
-		return nil, err
-	}
-	return parsePackages(res.StdoutString()), nil
-}
-
-func parsePackages(stdout string) []string {
-	packages := []string{}
-	for line := range strings.SplitSeq(stdout, "\n") {
-		if pkg, ok := strings.CutPrefix(strings.TrimSpace(line), "package:"); ok && pkg != "" {
-			packages = append(packages, pkg)
-		}
-	}
-	return packages
-}
-
 // GrantPermission grants a runtime permission, equivalent to
 // `adb shell pm grant <pkg> <permission>`.
 func (d Device) GrantPermission(ctx context.Context, pkg, permission string) error {
@@ -301,9 +322,11 @@ func (d Device) RevokePermission(ctx context.Context, pkg, permission string) er
 }
 
 // StartActivity launches an activity by component name (for example
-// "com.example/.MainActivity"), equivalent to `adb shell am start -n`.
+// "com.example/.MainActivity"), equivalent to `adb shell am start -n`. Use
+// [Device.StartIntent] for anything beyond a bare component.
 func (d Device) StartActivity(ctx context.Context, component string) error {
-	return d.execChecked(ctx, "shell", "am", "start", "-n", component)
+	_, err := d.StartIntent(ctx, Intent{Component: component}, StartOptions{})
+	return err
 }
 
 // ScreenResolution returns the device's physical screen resolution, equivalent
@@ -42,6 +42,7 @@ type Device struct {
 	addr       netip.AddrPort
 	hasAddr    bool
 	authorized bool
+	target     inputTarget
 }
 
 // Serial returns the adb serial that addresses the device. For network devices
@@ -0,0 +1,130 @@
+package adb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+// Dial opens a connection to a socket on the device through the adb server,
+// without creating a forward. service is the device endpoint, for example
+// [TCP](8080) or [LocalAbstract]("chrome_devtools_remote"). The context bounds
+// only the connection setup; closing the returned connection releases it.
+//
+// Dial talks to the adb server directly (see [WithServerAddr]) and starts it
+// with `adb start-server` if it is not running.
+func (d Device) Dial(ctx context.Context, service Endpoint) (net.Conn, error) {
+	conn, err := d.client.dialServer(ctx)
+	if errors.Is(err, syscall.ECONNREFUSED) {
+		if err := d.client.StartServer(ctx); err != nil {
+			return nil, err
+		}
+		conn, err = d.client.dialServer(ctx)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+	// Cancelling ctx mid-handshake unblocks the reads below by expiring the
+	// connection deadline.
+	stop := context.AfterFunc(ctx, func() { _ = conn.SetDeadline(time.Unix(1, 0)) })
+	err = serverRequest(conn, "host:transport:"+d.serial)
+	if err == nil {
+		err = serverRequest(conn, service.String())
+	}
+	if !stop() || ctx.Err() != nil {
+		err = ctx.Err()
+	}
+	if err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+	_ = conn.SetDeadline(time.Time{})
+	return conn, nil
+}
+
+func (c *Client) dialServer(ctx context.Context) (net.Conn, error) {
+	var dialer net.Dialer
+	return dialer.DialContext(ctx, "tcp", c.serverAddr)
+}
+
+// serverRequest sends one adb server request (a 4-digit hex length followed
+// by the payload) and reads the reply: "OKAY", or "FAIL" followed by a
+// length-prefixed message.
+func serverRequest(conn net.Conn, req string) error {
+	if _, err := fmt.Fprintf(conn, "%04x%s", len(req), req); err != nil {
+		return err
+	}
+	status := make([]byte, 4)
+	if _, err := io.ReadFull(conn, status); err != nil {
+		return err
+	}
+	switch string(status) {
+	case "OKAY":
+		return nil
+	case "FAIL":
+		msg, err := readServerString(conn)
+		if err != nil {
+			return err
+		}
+		cause := filterStderr(msg)
+		if cause == nil {
+			cause = ErrCommandFailed
+		}
+		return fmt.Errorf("adb server %s: %w: %s", req, cause, msg)
+	default:
+		return fmt.Errorf("adb server %s: unexpected reply %q", req, status)
+	}
+}
+
+func readServerString(r io.Reader) (string, error) {
+	hexLen := make([]byte, 4)
+	if _, err := io.ReadFull(r, hexLen); err != nil {
+		return "", err
+	}
+	n, err := strconv.ParseUint(string(hexLen), 16, 16)
+	if err != nil {
+		return "", fmt.Errorf("adb server: bad length %q", hexLen)
+	}
+	buf := make([]byte, n)
+	if _, err := io.ReadFull(r, buf); err != nil {
+		return "", err
+	}
+	return string(buf), nil
+}
+
+// HTTPTransport returns an HTTP transport whose connections are made with
+// [Device.Dial], for talking to HTTP servers on the device without a forward.
+// If endpoint is empty, each request goes to the device TCP port in its URL
+// (the host is ignored, so "http://localhost:8080/" reaches device port
+// 8080); otherwise every request goes to endpoint, for example
+// [LocalAbstract]("chrome_devtools_remote"):
+//
+//	client := &http.Client{Transport: d.HTTPTransport(adb.LocalAbstract("chrome_devtools_remote"))}
+//	resp, err := client.Get("http://devtools/json/version")
+func (d Device) HTTPTransport(endpoint Endpoint) *http.Transport {
+	return &http.Transport{
+		DialContext: func(ctx context.Context, _, addr string) (net.Conn, error) {
+			if endpoint != "" {
+				return d.Dial(ctx, endpoint)
+			}
+			_, port, err := net.SplitHostPort(addr)
+			if err != nil {
+				return nil, err
+			}
+			n, err := strconv.ParseUint(port, 10, 16)
+			if err != nil {
+				return nil, fmt.Errorf("invalid port in %q", addr)
+			}
+			return d.Dial(ctx, TCP(uint16(n)))
+		},
+	}
+}
@@ -0,0 +1,118 @@
+package adb
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"testing"
+)
+
+// fakeServer starts an adb server stub that accepts a single connection per
+// call to serve, answers the transport and service requests with the given
+// replies (an empty reply means OKAY), then hands the connection to handle.
+func fakeServer(t *testing.T) (*Client, func(replies []string, handle func(net.Conn)) <-chan []string) {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	t.Cleanup(func() { _ = ln.Close() })
+	c, err := New(WithBinary("adb"), WithServerAddr(ln.Addr().String()))
+	if err != nil {
+		t.Fatalf("New(): %v", err)
+	}
+	serve := func(replies []string, handle func(net.Conn)) <-chan []string {
+		reqs := make(chan []string, 1)
+		go func() {
+			var got []string
+			defer func() { reqs <- got }()
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+			for _, reply := range replies {
+				req, err := readServerString(conn)
+				if err != nil {
+					return
+				}
+				got = append(got, req)
+				if reply == "" {
+					_, _ = io.WriteString(conn, "OKAY")
+					continue
+				}
+				_, _ = fmt.Fprintf(conn, "FAIL%04x%s", len(reply), reply)
+				return
+			}
+			if handle != nil {
+				handle(conn)
+			}
+		}()
+		return reqs
+	}
+	return c, serve
+}
+
+func TestDial(t *testing.T) {
+	c, serve := fakeServer(t)
+	reqs := serve([]string{"", ""}, func(conn net.Conn) {
+		_, _ = io.WriteString(conn, "hello")
+	})
+	conn, err := c.Device("S").Dial(context.Background(), LocalAbstract("chrome_devtools_remote"))
+	if err != nil {
+		t.Fatalf("Dial() error = %v", err)
+	}
+	defer conn.Close()
+	got, err := io.ReadAll(conn)
+	if err != nil || string(got) != "hello" {
+		t.Fatalf("read = %q, %v", got, err)
+	}
+	want := []string{"host:transport:S", "localabstract:chrome_devtools_remote"}
+	if r := <-reqs; fmt.Sprint(r) != fmt.Sprint(want) {
+		t.Fatalf("requests = %q, want %q", r, want)
+	}
+}
+
+func TestDial_Failure(t *testing.T) {
+	c, serve := fakeServer(t)
+	serve([]string{"device 'S' not found"}, nil)
+	if _, err := c.Device("S").Dial(context.Background(), TCP(8080)); !errors.Is(err, ErrDeviceNotFound) {
+		t.Fatalf("Dial() error = %v, want ErrDeviceNotFound", err)
+	}
+
+	serve([]string{"", "connection refused"}, nil)
+	if _, err := c.Device("S").Dial(context.Background(), TCP(8080)); !errors.Is(err, ErrCommandFailed) {
+		t.Fatalf("Dial() error = %v, want ErrCommandFailed", err)
+	}
+}
+
+func TestHTTPTransport(t *testing.T) {
+	c, serve := fakeServer(t)
+	reqs := serve([]string{"", ""}, func(conn net.Conn) {
+		req, err := http.ReadRequest(bufio.NewReader(conn))
+		if err != nil {
+			return
+		}
+		body := "path=" + req.URL.Path
+		_, _ = io.WriteString(conn, "HTTP/1.1 200 OK\r\nConnection: close\r\nContent-Length: "+
+			strconv.Itoa(len(body))+"\r\n\r\n"+body)
+	})
+	client := &http.Client{Transport: c.Device("S").HTTPTransport("")}
+	resp, err := client.Get("http://localhost:8080/status")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	if string(body) != "path=/status" {
+		t.Fatalf("body = %q", body)
+	}
+	if r := <-reqs; len(r) != 2 || r[1] != "tcp:8080" {
+		t.Fatalf("requests = %q, want service tcp:8080", r)
+	}
+}
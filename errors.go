@@ -37,6 +37,38 @@ var (
 	ErrConnectionRefused = errors.New("connection refused")
 	// ErrMoreThanOneDevice is returned when multiple devices are connected and no serial is specified.
 	ErrMoreThanOneDevice = errors.New("more than one device/emulator; select a device")
+	// ErrUIHierarchyParseFail is returned when uiautomator output does not
+	// contain a parseable UI hierarchy.
+	ErrUIHierarchyParseFail = errors.New("failed to parse UI hierarchy from uiautomator output")
+	// ErrElementNotFound is returned when no UI node matches a selector.
+	ErrElementNotFound = errors.New("no UI element matches the selector")
+	// ErrPackageNotFound is returned when a package is not installed.
+	ErrPackageNotFound = errors.New("package not found")
+	// ErrUntypeableText is returned when text contains characters that
+	// `input text` cannot type and no fallback is configured.
+	ErrUntypeableText = errors.New("text contains characters input text cannot type")
+	// ErrClipboardUnavailable is returned when the device offers no way to
+	// access the clipboard from the shell.
+	ErrClipboardUnavailable = errors.New("clipboard is not accessible from the shell")
+	// ErrProcessNotFound is returned when a package has no running process.
+	ErrProcessNotFound = errors.New("process not found")
+	// ErrInvalidInterval is returned when a sampling interval is not
+	// positive.
+	ErrInvalidInterval = errors.New("sampling interval must be positive")
+	// ErrNoAPKs is returned when an install is given no APK files.
+	ErrNoAPKs = errors.New("no APKs to install")
+	// ErrInvalidAPK is returned when a host-side APK cannot be parsed.
+	ErrInvalidAPK = errors.New("invalid APK")
+	// ErrIncompatibleABI is returned when an APK's native libraries support
+	// none of the device's ABIs.
+	ErrIncompatibleABI = errors.New("APK native code is incompatible with the device ABIs")
+	// ErrInvalidGesture is returned when a [Gesture] has no pointers, a
+	// pointer without points, more pointers than the touchscreen tracks, or a
+	// display it cannot reach.
+	ErrInvalidGesture = errors.New("invalid gesture")
+	// ErrTouchscreenNotFound is returned when the device has no multi-touch
+	// input device to inject multi-pointer gestures into.
+	ErrTouchscreenNotFound = errors.New("no multi-touch touchscreen found")
 	// ErrCommandFailed is returned when adb exits successfully but its output
 	// reports a failure (for example `Failure [INSTALL_FAILED_*]` or an
 	// on-device Exception).
@@ -71,3 +103,34 @@ func (e *CommandError) Error() string {
 // Unwrap returns the underlying error so [errors.Is] and [errors.As] can match
 // against the package sentinels.
 func (e *CommandError) Unwrap() error { return e.Err }
+
+// InstallError describes a package-manager install failure reported as
+// `Failure [INSTALL_FAILED_*: detail]`. It unwraps to [ErrCommandFailed], and
+// [errors.Is] also matches another *InstallError with the same Reason, so
+// callers can test for a specific failure:
+//
+//	if errors.Is(err, &adb.InstallError{Reason: adb.InstallFailedUpdateIncompatible}) {
+//		// signature mismatch: uninstall and retry
+//	}
+type InstallError struct {
+	// Reason is the failure code, for example [InstallFailedVersionDowngrade].
+	Reason InstallFailureReason
+	// Detail is the optional human-readable explanation following the code.
+	Detail string
+}
+
+func (e *InstallError) Error() string {
+	if e.Detail == "" {
+		return "install failed: " + string(e.Reason)
+	}
+	return "install failed: " + string(e.Reason) + ": " + e.Detail
+}
+
+// Unwrap returns [ErrCommandFailed].
+func (e *InstallError) Unwrap() error { return ErrCommandFailed }
+
+// Is reports whether target is an *InstallError with the same Reason.
+func (e *InstallError) Is(target error) bool {
+	t, ok := target.(*InstallError)
+	return ok && t.Reason == e.Reason
+}
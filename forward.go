@@ -0,0 +1,135 @@
+package adb
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Endpoint is an adb socket specification, as used by forward and reverse,
+// for example "tcp:8080" or "localabstract:chrome_devtools_remote". Build one
+// with the helpers below or convert a raw spec string.
+type Endpoint string
+
+// TCP returns the endpoint for a TCP port ("tcp:<port>"). Port 0 asks adb to
+// allocate a free port when used as the host side of a forward.
+func TCP(port uint16) Endpoint { return Endpoint("tcp:" + strconv.Itoa(int(port))) }
+
+// LocalAbstract returns the endpoint for a Unix socket in the abstract
+// namespace ("localabstract:<name>").
+func LocalAbstract(name string) Endpoint { return Endpoint("localabstract:" + name) }
+
+// LocalReserved returns the endpoint for a reserved Unix socket under
+// /dev/socket ("localreserved:<name>").
+func LocalReserved(name string) Endpoint { return Endpoint("localreserved:" + name) }
+
+// LocalFilesystem returns the endpoint for a Unix socket at a filesystem
+// path ("localfilesystem:<path>").
+func LocalFilesystem(path string) Endpoint { return Endpoint("localfilesystem:" + path) }
+
+// JDWP returns the endpoint for the Java debugger of process pid
+// ("jdwp:<pid>"). It is only valid as the device side of a forward.
+func JDWP(pid int) Endpoint { return Endpoint("jdwp:" + strconv.Itoa(pid)) }
+
+// String returns the socket specification.
+func (e Endpoint) String() string { return string(e) }
+
+// PortForward is a forward from an adb-allocated host TCP port to a device
+// endpoint, created by [Device.ForwardTCP] or [Device.ForwardEndpoint]. Close
+// it to remove the forward.
+type PortForward struct {
+	device Device
+	// Port is the host TCP port adb allocated.
+	Port uint16
+	// Remote is the device endpoint connections are forwarded to.
+	Remote Endpoint
+	closed bool
+}
+
+// Local returns the host endpoint of the forward.
+func (f *PortForward) Local() Endpoint { return TCP(f.Port) }
+
+// Addr returns the host address to dial, for example "127.0.0.1:41235".
+func (f *PortForward) Addr() string { return "127.0.0.1:" + strconv.Itoa(int(f.Port)) }
+
+// Close removes the forward. Later calls do nothing. It is not safe to call
+// concurrently.
+func (f *PortForward) Close() error {
+	if f.closed {
+		return nil
+	}
+	f.closed = true
+	return f.device.RemoveForward(context.Background(), f.Local().String())
+}
+
+// ForwardTCP forwards a free host TCP port to a device TCP port, equivalent to
+// `adb forward tcp:0 tcp:<remotePort>`. The returned handle reports the
+// allocated port; Close it when done.
+func (d Device) ForwardTCP(ctx context.Context, remotePort uint16) (*PortForward, error) {
+	return d.ForwardEndpoint(ctx, TCP(remotePort))
+}
+
+// ForwardEndpoint forwards a free host TCP port to any device endpoint, for
+// example a [LocalAbstract] socket, equivalent to `adb forward tcp:0 <remote>`.
+func (d Device) ForwardEndpoint(ctx context.Context, remote Endpoint) (*PortForward, error) {
+	args := []string{"-s", d.serial, "forward", TCP(0).String(), remote.String()}
+	res, err := d.client.run(ctx, args...)
+	if err != nil {
+		return nil, err
+	}
+	port, err := strconv.ParseUint(strings.TrimSpace(res.StdoutString()), 10, 16)
+	if err != nil || port == 0 {
+		return nil, &CommandError{
+			Args:   args,
+			Code:   res.Code,
+			Stderr: res.StderrString(),
+			Err:    fmt.Errorf("%w: unexpected forward output %q", ErrCommandFailed, res.StdoutString()),
+		}
+	}
+	return &PortForward{device: d, Port: uint16(port), Remote: remote}, nil
+}
+
+// ForwardRule is an active forward or reverse. Local is always the host side
+// and Remote the device side.
+type ForwardRule struct {
+	Local  Endpoint
+	Remote Endpoint
+}
+
+// ListForwards returns the device's host-to-device forwards, parsed from
+// `adb forward --list`.
+func (d Device) ListForwards(ctx context.Context) ([]ForwardRule, error) {
+	res, err := d.client.run(ctx, "-s", d.serial, "forward", "--list")
+	if err != nil {
+		return nil, err
+	}
+	// The list covers every device; each line is "<serial> <local> <remote>".
+	var rules []ForwardRule
+	for line := range strings.SplitSeq(res.StdoutString(), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 3 && fields[0] == d.serial {
+			rules = append(rules, ForwardRule{Local: Endpoint(fields[1]), Remote: Endpoint(fields[2])})
+		}
+	}
+	return rules, nil
+}
+
+// ListReverses returns the device's device-to-host reverses, parsed from
+// `adb reverse --list`.
+func (d Device) ListReverses(ctx context.Context) ([]ForwardRule, error) {
+	res, err := d.client.run(ctx, "-s", d.serial, "reverse", "--list")
+	if err != nil {
+		return nil, err
+	}
+	// Each line is "<transport> <device side> <host side>", where the
+	// transport is a device-side name such as "UsbFfs" rather than a serial.
+	var rules []ForwardRule
+	for line := range strings.SplitSeq(res.StdoutString(), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 3 {
+			rules = append(rules, ForwardRule{Local: Endpoint(fields[2]), Remote: Endpoint(fields[1])})
+		}
+	}
+	return rules, nil
+}
@@ -0,0 +1,84 @@
+package adb
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestEndpoints(t *testing.T) {
+	tests := map[Endpoint]string{
+		TCP(8080):                               "tcp:8080",
+		LocalAbstract("chrome_devtools_remote"): "localabstract:chrome_devtools_remote",
+		LocalReserved("debuggerd"):              "localreserved:debuggerd",
+		LocalFilesystem("/data/local/tmp/sock"): "localfilesystem:/data/local/tmp/sock",
+		JDWP(4321):                              "jdwp:4321",
+	}
+	for e, want := range tests {
+		if e.String() != want {
+			t.Fatalf("endpoint = %q, want %q", e, want)
+		}
+	}
+}
+
+func TestForwardTCP(t *testing.T) {
+	c, calls := fakeADBSeq(t, fakeResponse{stdout: "41235\n"}, fakeResponse{})
+	f, err := c.Device("S").ForwardTCP(context.Background(), 8080)
+	if err != nil {
+		t.Fatalf("ForwardTCP() error = %v", err)
+	}
+	if f.Port != 41235 || f.Addr() != "127.0.0.1:41235" || f.Remote != "tcp:8080" {
+		t.Fatalf("ForwardTCP() = %+v", f)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatalf("second Close() error = %v", err)
+	}
+	want := [][]string{
+		{"-s", "S", "forward", "tcp:0", "tcp:8080"},
+		{"-s", "S", "forward", "--remove", "tcp:41235"},
+	}
+	if got := calls(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("calls = %v, want %v", got, want)
+	}
+}
+
+func TestForwardEndpoint_BadOutput(t *testing.T) {
+	c, _ := fakeADB(t, "", "", 0)
+	if _, err := c.Device("S").ForwardEndpoint(context.Background(), LocalAbstract("x")); !errors.Is(err, ErrCommandFailed) {
+		t.Fatalf("ForwardEndpoint() error = %v, want ErrCommandFailed", err)
+	}
+}
+
+func TestListForwards(t *testing.T) {
+	c, argsFile := fakeADB(t, "S tcp:41235 tcp:8080\nOTHER tcp:5000 tcp:5000\nS tcp:9222 localabstract:chrome_devtools_remote\n", "", 0)
+	got, err := c.Device("S").ListForwards(context.Background())
+	if err != nil {
+		t.Fatalf("ListForwards() error = %v", err)
+	}
+	want := []ForwardRule{
+		{Local: "tcp:41235", Remote: "tcp:8080"},
+		{Local: "tcp:9222", Remote: "localabstract:chrome_devtools_remote"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("ListForwards() = %v, want %v", got, want)
+	}
+	if args, want := readArgs(t, argsFile), []string{"-s", "S", "forward", "--list"}; !reflect.DeepEqual(args, want) {
+		t.Fatalf("ListForwards() args = %v, want %v", args, want)
+	}
+}
+
+func TestListReverses(t *testing.T) {
+	c, _ := fakeADB(t, "UsbFfs tcp:8081 tcp:3000\n", "", 0)
+	got, err := c.Device("S").ListReverses(context.Background())
+	if err != nil {
+		t.Fatalf("ListReverses() error = %v", err)
+	}
+	want := []ForwardRule{{Local: "tcp:3000", Remote: "tcp:8081"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("ListReverses() = %v, want %v", got, want)
+	}
+}
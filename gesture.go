@@ -0,0 +1,438 @@
+package adb
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Point is a screen coordinate in pixels.
+type Point struct {
+	X int `json:"x"`
+	Y int `json:"y"`
+}
+
+// Gesture is a touch gesture made of one or more simultaneous pointers
+// (fingers). Every pointer touches down on the first point of its path at the
+// same moment, rests there for Hold, then moves through the rest of its path
+// over Duration, spending equal time on each segment, and lifts.
+//
+// Build common gestures with [Pinch], [Rotate], [MultiSwipe], and
+// [DragAndDrop], perform them with [Device.Gesture], or add them to a
+// [Sequence] with [NewGesture].
+type Gesture struct {
+	// Pointers holds the path of each finger. Every path needs at least one
+	// point; a single point keeps that finger still.
+	Pointers [][]Point
+	// Hold is how long the fingers rest on their first point before moving.
+	Hold time.Duration
+	// Duration is how long the fingers take to travel their paths.
+	Duration time.Duration
+}
+
+// rotateStep is the largest angle, in degrees, between the points of a
+// [Rotate] path, so the fingers follow the arc rather than its chord.
+const rotateStep = 15.0
+
+// Pinch returns a two-finger pinch centred on c. The fingers start from
+// pixels either side of the centre on a horizontal line and end to pixels
+// either side, so to < from pinches in (zooms out) and to > from spreads the
+// fingers apart (zooms in).
+func Pinch(c Point, from, to int, d time.Duration) Gesture {
+	return Gesture{
+		Pointers: [][]Point{
+			{{c.X - from, c.Y}, {c.X - to, c.Y}},
+			{{c.X + from, c.Y}, {c.X + to, c.Y}},
+		},
+		Duration: d,
+	}
+}
+
+// Rotate returns a two-finger rotation by degrees around c, with the fingers
+// held radius pixels either side of the centre. Positive angles turn
+// clockwise on screen.
+func Rotate(c Point, radius int, degrees float64, d time.Duration) Gesture {
+	steps := max(1, int(math.Ceil(math.Abs(degrees)/rotateStep)))
+	g := Gesture{Pointers: make([][]Point, 2), Duration: d}
+	for finger := range g.Pointers {
+		start := float64(finger) * 180
+		path := make([]Point, steps+1)
+		for i := range path {
+			rad := (start + degrees*float64(i)/float64(steps)) * math.Pi / 180
+			path[i] = Point{
+				X: c.X + int(math.Round(float64(radius)*math.Cos(rad))),
+				Y: c.Y + int(math.Round(float64(radius)*math.Sin(rad))),
+			}
+		}
+		g.Pointers[finger] = path
+	}
+	return g
+}
+
+// MultiSwipe returns a swipe of n fingers from one point to another over d.
+// The fingers are lined up spacing pixels apart across the direction of
+// travel and centred on the swipe line. For n < 1 it returns a gesture with
+// no pointers, which [Device.Gesture] rejects with [ErrInvalidGesture].
+func MultiSwipe(n int, from, to Point, spacing int, d time.Duration) Gesture {
+	// Unit vector perpendicular to the swipe; horizontal when the swipe has
+	// no direction.
+	px, py := 1.0, 0.0
+	if dx, dy := float64(to.X-from.X), float64(to.Y-from.Y); dx != 0 || dy != 0 {
+		l := math.Hypot(dx, dy)
+		px, py = -dy/l, dx/l
+	}
+	g := Gesture{Pointers: make([][]Point, max(n, 0)), Duration: d}
+	for i := range g.Pointers {
+		off := (float64(i) - float64(n-1)/2) * float64(spacing)
+		ox, oy := int(math.Round(off*px)), int(math.Round(off*py))
+		g.Pointers[i] = []Point{{from.X + ox, from.Y + oy}, {to.X + ox, to.Y + oy}}
+	}
+	return g
+}
+
+// DragAndDrop returns a single-finger drag that presses on from for hold
+// (long enough for the view under it to start a drag) and then moves to to
+// over d before releasing.
+func DragAndDrop(from, to Point, hold, d time.Duration) Gesture {
+	return Gesture{Pointers: [][]Point{{from, to}}, Hold: hold, Duration: d}
+}
+
+// length returns the total time the gesture takes to perform.
+func (g Gesture) length() time.Duration { return g.Hold + g.Duration }
+
+// clone returns a deep copy of g, so a [Sequence] does not share paths with
+// its callers.
+func (g *Gesture) clone() *Gesture {
+	if g == nil {
+		return nil
+	}
+	out := *g
+	out.Pointers = slices.Clone(g.Pointers)
+	for i, path := range out.Pointers {
+		out.Pointers[i] = slices.Clone(path)
+	}
+	return &out
+}
+
+func (g Gesture) validate() error {
+	if len(g.Pointers) == 0 {
+		return fmt.Errorf("%w: no pointers", ErrInvalidGesture)
+	}
+	for i, path := range g.Pointers {
+		if len(path) == 0 {
+			return fmt.Errorf("%w: pointer %d has no points", ErrInvalidGesture, i)
+		}
+	}
+	if g.Hold < 0 || g.Duration < 0 {
+		return fmt.Errorf("%w: negative duration", ErrInvalidGesture)
+	}
+	return nil
+}
+
+const (
+	// evdevInterval is the time between frames of a multi-pointer gesture.
+	evdevInterval = 20 * time.Millisecond
+	// maxGestureFrames caps the frames of a long gesture so the generated
+	// shell script stays small.
+	maxGestureFrames = 100
+)
+
+// frames samples every pointer's position at evenly spaced moments of the
+// movement, about interval apart. It returns one row of positions per frame,
+// the last of which is the end of every path, and the time between frames.
+func (g Gesture) frames(interval time.Duration) ([][]Point, time.Duration) {
+	n := min(max(int(g.Duration/interval), 1), maxGestureFrames)
+	out := make([][]Point, n)
+	for i := range out {
+		t := float64(i+1) / float64(n)
+		row := make([]Point, len(g.Pointers))
+		for p, path := range g.Pointers {
+			row[p] = pathAt(path, t)
+		}
+		out[i] = row
+	}
+	return out, g.Duration / time.Duration(n)
+}
+
+// pathAt returns the position a fraction t (0 to 1) of the way along path,
+// giving each segment an equal share of the time.
+func pathAt(path []Point, t float64) Point {
+	segs := len(path) - 1
+	if segs <= 0 || t <= 0 {
+		return path[0]
+	}
+	if t >= 1 {
+		return path[segs]
+	}
+	u := t * float64(segs)
+	i := int(u)
+	f := u - float64(i)
+	a, b := path[i], path[i+1]
+	return Point{
+		X: a.X + int(math.Round(f*float64(b.X-a.X))),
+		Y: a.Y + int(math.Round(f*float64(b.Y-a.Y))),
+	}
+}
+
+// Gesture performs g on the device.
+//
+// Single-pointer gestures use `input` in screen coordinates of the current
+// orientation, honouring [Device.WithInputSource] and [Device.OnDisplay]. A
+// straight move without a hold, or a press that stays on one point, is a
+// single `input swipe`, which the device interpolates and times itself. Any
+// other single-pointer gesture is a shell script of `input motionevent`
+// commands (Android 10 or later): one DOWN, one MOVE per path point after
+// the first, and one UP. The pointer jumps between points rather than
+// sliding, and since every command starts its own `input` process, which
+// takes around a hundred milliseconds, each point arrives that much later
+// than scheduled; use few points and avoid this form for flings.
+//
+// Gestures with several pointers cannot be expressed through `input`, so
+// they are written to the touchscreen as raw multi-touch events with
+// `sendevent`; their coordinates are in the display's natural orientation
+// and are scaled to the touchscreen's axis ranges, and they can only target
+// the built-in display. This requires the shell user to have access to
+// /dev/input, which it does on stock builds. Each event is a separate
+// `sendevent` process, so frames also run somewhat later than scheduled.
+func (d Device) Gesture(ctx context.Context, g Gesture) error {
+	if err := g.validate(); err != nil {
+		return err
+	}
+	if len(g.Pointers) == 1 {
+		return d.execChecked(ctx, append([]string{"shell"}, d.singlePointerArgs(g)...)...)
+	}
+	if id, ok := d.Display(); ok && id != 0 {
+		return fmt.Errorf("%w: multi-pointer gestures cannot target display %d", ErrInvalidGesture, id)
+	}
+	ts, err := d.touchscreen(ctx)
+	if err != nil {
+		return err
+	}
+	if len(g.Pointers) > ts.slots {
+		return fmt.Errorf("%w: %d pointers, touchscreen tracks %d", ErrInvalidGesture, len(g.Pointers), ts.slots)
+	}
+	res, err := d.ScreenResolution(ctx)
+	if err != nil {
+		return err
+	}
+	return d.execChecked(ctx, "shell", ts.script(g, res))
+}
+
+// singlePointerArgs returns the shell command for a single-pointer gesture:
+// an `input swipe` when one can express it, and a motion event script
+// otherwise.
+func (d Device) singlePointerArgs(g Gesture) []string {
+	path := g.Pointers[0]
+	if len(path) == 1 || (len(path) == 2 && g.Hold == 0) {
+		from, to := path[0], path[len(path)-1]
+		return d.inputArgs("swipe",
+			strconv.Itoa(from.X), strconv.Itoa(from.Y),
+			strconv.Itoa(to.X), strconv.Itoa(to.Y),
+			strconv.FormatInt(g.length().Milliseconds(), 10),
+		)
+	}
+	return []string{motionEventScript(g, strings.Join(d.inputArgs("motionevent"), " "))}
+}
+
+// motionEventScript renders a single-pointer gesture as a shell script of
+// motion events, each sent with the command line in motionevent (such as
+// "input motionevent"). The pointer moves once per path point, with the
+// gesture's duration split evenly between them.
+func motionEventScript(g Gesture, motionevent string) string {
+	var b strings.Builder
+	motion := func(action string, p Point) {
+		fmt.Fprintf(&b, "%s %s %d %d;", motionevent, action, p.X, p.Y)
+	}
+	b.WriteString("set -e;")
+	path := g.Pointers[0]
+	motion("DOWN", path[0])
+	writeSleep(&b, g.Hold)
+	step := g.Duration / time.Duration(len(path)-1)
+	for _, p := range path[1:] {
+		writeSleep(&b, step)
+		motion("MOVE", p)
+	}
+	motion("UP", path[len(path)-1])
+	return b.String()
+}
+
+// writeSleep appends a shell sleep for d, which may be fractional. It writes
+// nothing for a non-positive duration.
+func writeSleep(b *strings.Builder, d time.Duration) {
+	if d <= 0 {
+		return
+	}
+	fmt.Fprintf(b, "sleep %s;", strconv.FormatFloat(d.Seconds(), 'f', -1, 64))
+}
+
+// Linux input event types and codes used to synthesize multi-touch gestures
+// (see linux/input-event-codes.h).
+const (
+	evSyn = 0x00
+	evKey = 0x01
+	evAbs = 0x03
+
+	synReport = 0x00
+	btnTouch  = 0x14a
+
+	absMTSlot       = 0x2f
+	absMTPositionX  = 0x35
+	absMTPositionY  = 0x36
+	absMTTrackingID = 0x39
+	absMTPressure   = 0x3a
+)
+
+// absRange is the value range of an evdev absolute axis.
+type absRange struct {
+	min, max int
+	valid    bool
+}
+
+// scale maps a screen coordinate in [0, size) onto the axis range.
+func (a absRange) scale(v, size int) int {
+	if size <= 1 {
+		return a.min
+	}
+	raw := a.min + v*(a.max-a.min)/(size-1)
+	return min(max(raw, a.min), a.max)
+}
+
+// touchscreen describes a protocol-B multi-touch input device, as reported by
+// `getevent -pl`.
+type touchscreen struct {
+	path     string
+	x, y     absRange
+	pressure absRange
+	slots    int
+	direct   bool
+}
+
+// touchscreen finds the device's multi-touch touchscreen, equivalent to
+// `adb shell getevent -pl`.
+func (d Device) touchscreen(ctx context.Context) (touchscreen, error) {
+	res, err := d.client.run(ctx, "-s", d.serial, "shell", "getevent", "-pl")
+	if err != nil {
+		return touchscreen{}, err
+	}
+	return parseTouchscreen(res.StdoutString())
+}
+
+var (
+	reInputDevice = regexp.MustCompile(`^add device \d+: (\S+)`)
+	reAbsAxis     = regexp.MustCompile(`(ABS_MT_\w+)\s*:\s*value -?\d+, min (-?\d+), max (-?\d+)`)
+)
+
+// parseTouchscreen picks the multi-touch device out of `getevent -pl` output.
+// Only devices reporting slots (protocol B) and both position axes qualify,
+// and a direct-input touchscreen is preferred over, say, a touchpad.
+func parseTouchscreen(out string) (touchscreen, error) {
+	var devices []touchscreen
+	for line := range strings.SplitSeq(out, "\n") {
+		if m := reInputDevice.FindStringSubmatch(line); m != nil {
+			devices = append(devices, touchscreen{path: m[1]})
+			continue
+		}
+		if len(devices) == 0 {
+			continue
+		}
+		cur := &devices[len(devices)-1]
+		if strings.Contains(line, "INPUT_PROP_DIRECT") {
+			cur.direct = true
+			continue
+		}
+		m := reAbsAxis.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		lo, _ := strconv.Atoi(m[2])
+		hi, _ := strconv.Atoi(m[3])
+		r := absRange{min: lo, max: hi, valid: true}
+		switch m[1] {
+		case "ABS_MT_SLOT":
+			cur.slots = hi + 1
+		case "ABS_MT_POSITION_X":
+			cur.x = r
+		case "ABS_MT_POSITION_Y":
+			cur.y = r
+		case "ABS_MT_PRESSURE":
+			cur.pressure = r
+		}
+	}
+	var found *touchscreen
+	for i, ts := range devices {
+		if ts.slots == 0 || !ts.x.valid || !ts.y.valid {
+			continue
+		}
+		if found == nil || (ts.direct && !found.direct) {
+			found = &devices[i]
+		}
+	}
+	if found == nil {
+		return touchscreen{}, ErrTouchscreenNotFound
+	}
+	return *found, nil
+}
+
+// script renders a multi-pointer gesture as a shell script of `sendevent`
+// commands, using one multi-touch slot per pointer.
+func (t touchscreen) script(g Gesture, res Resolution) string {
+	var b strings.Builder
+	// A shell function keeps the script short: the device path would
+	// otherwise be repeated on every event.
+	fmt.Fprintf(&b, "set -e;e(){ sendevent %s $1 $2 $3;};", shellQuote(t.path))
+	emit := func(typ, code, value int) {
+		fmt.Fprintf(&b, "e %d %d %d;", typ, code, value)
+	}
+	raw := func(p Point) Point {
+		return Point{X: t.x.scale(p.X, res.Width), Y: t.y.scale(p.Y, res.Height)}
+	}
+
+	last := make([]Point, len(g.Pointers))
+	for i, path := range g.Pointers {
+		last[i] = raw(path[0])
+		emit(evAbs, absMTSlot, i)
+		emit(evAbs, absMTTrackingID, i)
+		emit(evAbs, absMTPositionX, last[i].X)
+		emit(evAbs, absMTPositionY, last[i].Y)
+		// Android treats a contact with zero pressure as hovering.
+		if t.pressure.valid {
+			emit(evAbs, absMTPressure, max(t.pressure.max/2, 1))
+		}
+	}
+	emit(evKey, btnTouch, 1)
+	emit(evSyn, synReport, 0)
+	writeSleep(&b, g.Hold)
+
+	frames, step := g.frames(evdevInterval)
+	for _, row := range frames {
+		writeSleep(&b, step)
+		for i, p := range row {
+			p = raw(p)
+			if p == last[i] {
+				continue
+			}
+			emit(evAbs, absMTSlot, i)
+			if p.X != last[i].X {
+				emit(evAbs, absMTPositionX, p.X)
+			}
+			if p.Y != last[i].Y {
+				emit(evAbs, absMTPositionY, p.Y)
+			}
+			last[i] = p
+		}
+		emit(evSyn, synReport, 0)
+	}
+
+	for i := range g.Pointers {
+		emit(evAbs, absMTSlot, i)
+		emit(evAbs, absMTTrackingID, -1)
+	}
+	emit(evKey, btnTouch, 0)
+	emit(evSyn, synReport, 0)
+	return b.String()
+}
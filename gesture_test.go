@@ -0,0 +1,321 @@
+package adb
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// geteventDevices is `getevent -pl` output listing a keyboard, an indirect
+// touchpad, and a direct touchscreen.
+const geteventDevices = `add device 1: /dev/input/event0
+  name:     "gpio-keys"
+  events:
+    KEY (0001): KEY_VOLUMEDOWN        KEY_VOLUMEUP          KEY_POWER
+  input props:
+    <none>
+add device 2: /dev/input/event1
+  name:     "touchpad"
+  events:
+    KEY (0001): BTN_TOUCH
+    ABS (0003): ABS_MT_SLOT           : value 0, min 0, max 4, fuzz 0, flat 0, resolution 0
+                ABS_MT_POSITION_X     : value 0, min 0, max 4095, fuzz 0, flat 0, resolution 0
+                ABS_MT_POSITION_Y     : value 0, min 0, max 4095, fuzz 0, flat 0, resolution 0
+                ABS_MT_TRACKING_ID    : value 0, min 0, max 65535, fuzz 0, flat 0, resolution 0
+  input props:
+    INPUT_PROP_POINTER
+add device 3: /dev/input/event2
+  name:     "sec_touchscreen"
+  events:
+    KEY (0001): BTN_TOUCH
+    ABS (0003): ABS_MT_SLOT           : value 0, min 0, max 9, fuzz 0, flat 0, resolution 0
+                ABS_MT_POSITION_X     : value 0, min 0, max 1079, fuzz 0, flat 0, resolution 0
+                ABS_MT_POSITION_Y     : value 0, min 0, max 1919, fuzz 0, flat 0, resolution 0
+                ABS_MT_TRACKING_ID    : value 0, min 0, max 65535, fuzz 0, flat 0, resolution 0
+                ABS_MT_PRESSURE       : value 0, min 0, max 255, fuzz 0, flat 0, resolution 0
+  input props:
+    INPUT_PROP_DIRECT
+`
+
+func TestGestureBuilders(t *testing.T) {
+	tests := []struct {
+		name string
+		got  Gesture
+		want Gesture
+	}{
+		{
+			name: "pinch in",
+			got:  Pinch(Point{500, 1000}, 300, 100, time.Second),
+			want: Gesture{
+				Pointers: [][]Point{
+					{{200, 1000}, {400, 1000}},
+					{{800, 1000}, {600, 1000}},
+				},
+				Duration: time.Second,
+			},
+		},
+		{
+			name: "vertical three-finger swipe",
+			got:  MultiSwipe(3, Point{500, 1000}, Point{500, 200}, 100, 0),
+			want: Gesture{Pointers: [][]Point{
+				{{400, 1000}, {400, 200}},
+				{{500, 1000}, {500, 200}},
+				{{600, 1000}, {600, 200}},
+			}},
+		},
+		{
+			name: "drag and drop",
+			got:  DragAndDrop(Point{10, 20}, Point{30, 40}, time.Second, 200*time.Millisecond),
+			want: Gesture{
+				Pointers: [][]Point{{{10, 20}, {30, 40}}},
+				Hold:     time.Second,
+				Duration: 200 * time.Millisecond,
+			},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if !reflect.DeepEqual(tc.got, tc.want) {
+				t.Fatalf("got %+v, want %+v", tc.got, tc.want)
+			}
+		})
+	}
+}
+
+func TestRotate(t *testing.T) {
+	g := Rotate(Point{500, 500}, 100, 90, time.Second)
+	if len(g.Pointers) != 2 {
+		t.Fatalf("pointers = %d, want 2", len(g.Pointers))
+	}
+	wantEnds := [][2]Point{
+		{{600, 500}, {500, 600}},
+		{{400, 500}, {500, 400}},
+	}
+	for i, path := range g.Pointers {
+		// 90 degrees in 15 degree steps.
+		if len(path) != 7 {
+			t.Fatalf("pointer %d path len = %d, want 7", i, len(path))
+		}
+		if path[0] != wantEnds[i][0] || path[len(path)-1] != wantEnds[i][1] {
+			t.Fatalf("pointer %d = %v -> %v, want %v", i, path[0], path[len(path)-1], wantEnds[i])
+		}
+	}
+}
+
+func TestGestureFrames(t *testing.T) {
+	g := Gesture{
+		Pointers: [][]Point{{{0, 0}, {100, 0}, {100, 100}}, {{7, 7}}},
+		Duration: 40 * time.Millisecond,
+	}
+	frames, step := g.frames(10 * time.Millisecond)
+	want := [][]Point{
+		{{50, 0}, {7, 7}},
+		{{100, 0}, {7, 7}},
+		{{100, 50}, {7, 7}},
+		{{100, 100}, {7, 7}},
+	}
+	if !reflect.DeepEqual(frames, want) || step != 10*time.Millisecond {
+		t.Fatalf("frames() = %v, %v; want %v, 10ms", frames, step, want)
+	}
+
+	// An instantaneous gesture still moves to the end of every path.
+	frames, step = Gesture{Pointers: [][]Point{{{0, 0}, {10, 10}}}}.frames(10 * time.Millisecond)
+	if !reflect.DeepEqual(frames, [][]Point{{{10, 10}}}) || step != 0 {
+		t.Fatalf("instant frames() = %v, %v", frames, step)
+	}
+}
+
+func TestParseTouchscreen(t *testing.T) {
+	ts, err := parseTouchscreen(geteventDevices)
+	if err != nil {
+		t.Fatalf("parseTouchscreen() error = %v", err)
+	}
+	want := touchscreen{
+		path:     "/dev/input/event2",
+		x:        absRange{min: 0, max: 1079, valid: true},
+		y:        absRange{min: 0, max: 1919, valid: true},
+		pressure: absRange{min: 0, max: 255, valid: true},
+		slots:    10,
+		direct:   true,
+	}
+	if ts != want {
+		t.Fatalf("parseTouchscreen() = %+v, want %+v", ts, want)
+	}
+
+	if _, err := parseTouchscreen("add device 1: /dev/input/event0\n  name: \"gpio-keys\"\n"); !errors.Is(err, ErrTouchscreenNotFound) {
+		t.Fatalf("no touchscreen error = %v, want ErrTouchscreenNotFound", err)
+	}
+}
+
+func TestAbsRangeScale(t *testing.T) {
+	r := absRange{min: 0, max: 32767, valid: true}
+	for _, tc := range []struct{ v, want int }{
+		{0, 0},
+		{540, 16398},
+		{1079, 32767},
+		{5000, 32767},
+	} {
+		if got := r.scale(tc.v, 1080); got != tc.want {
+			t.Errorf("scale(%d) = %d, want %d", tc.v, got, tc.want)
+		}
+	}
+}
+
+func TestDeviceGesture_SinglePointer(t *testing.T) {
+	tests := []struct {
+		name string
+		g    Gesture
+		want []string
+	}{
+		{
+			name: "straight move is a swipe",
+			g:    Gesture{Pointers: [][]Point{{{10, 20}, {110, 20}}}, Duration: 150 * time.Millisecond},
+			want: []string{"input", "swipe", "10", "20", "110", "20", "150"},
+		},
+		{
+			name: "still press is a swipe in place",
+			g:    Gesture{Pointers: [][]Point{{{10, 20}}}, Hold: 500 * time.Millisecond, Duration: 100 * time.Millisecond},
+			want: []string{"input", "swipe", "10", "20", "10", "20", "600"},
+		},
+		{
+			name: "held drag moves once per point",
+			g:    DragAndDrop(Point{10, 20}, Point{110, 20}, 500*time.Millisecond, 100*time.Millisecond),
+			want: []string{"set -e;" +
+				"input motionevent DOWN 10 20;sleep 0.5;" +
+				"sleep 0.1;input motionevent MOVE 110 20;" +
+				"input motionevent UP 110 20;"},
+		},
+		{
+			name: "path splits the duration between points",
+			g:    Gesture{Pointers: [][]Point{{{0, 0}, {50, 0}, {50, 50}}}, Duration: 200 * time.Millisecond},
+			want: []string{"set -e;" +
+				"input motionevent DOWN 0 0;" +
+				"sleep 0.1;input motionevent MOVE 50 0;" +
+				"sleep 0.1;input motionevent MOVE 50 50;" +
+				"input motionevent UP 50 50;"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, argsFile := fakeADB(t, "", "", 0)
+			if err := fakeDevice(c, "S", USB).Gesture(context.Background(), tt.g); err != nil {
+				t.Fatalf("Gesture() error = %v", err)
+			}
+			want := append([]string{"-s", "S", "shell"}, tt.want...)
+			if got := readArgs(t, argsFile); !reflect.DeepEqual(got, want) {
+				t.Fatalf("args = %q, want %q", got, want)
+			}
+		})
+	}
+}
+
+func TestDeviceGesture_MultiPointer(t *testing.T) {
+	c, calls := fakeADBSeq(t,
+		fakeResponse{stdout: geteventDevices},
+		fakeResponse{stdout: "Physical size: 1080x1920\n"},
+		fakeResponse{},
+	)
+	d := fakeDevice(c, "S", USB)
+	if err := d.Gesture(context.Background(), Pinch(Point{540, 960}, 200, 100, 0)); err != nil {
+		t.Fatalf("Gesture() error = %v", err)
+	}
+	got := calls()
+	if len(got) != 3 {
+		t.Fatalf("calls = %q, want 3", got)
+	}
+	if want := []string{"-s", "S", "shell", "getevent", "-pl"}; !reflect.DeepEqual(got[0], want) {
+		t.Fatalf("touchscreen args = %q, want %q", got[0], want)
+	}
+	script := "set -e;e(){ sendevent /dev/input/event2 $1 $2 $3;};" +
+		// Touch down.
+		"e 3 47 0;e 3 57 0;e 3 53 340;e 3 54 960;e 3 58 127;" +
+		"e 3 47 1;e 3 57 1;e 3 53 740;e 3 54 960;e 3 58 127;" +
+		"e 1 330 1;e 0 0 0;" +
+		// Move; unchanged axes are not resent.
+		"e 3 47 0;e 3 53 440;e 3 47 1;e 3 53 640;e 0 0 0;" +
+		// Lift.
+		"e 3 47 0;e 3 57 -1;e 3 47 1;e 3 57 -1;e 1 330 0;e 0 0 0;"
+	if want := []string{"-s", "S", "shell", script}; !reflect.DeepEqual(got[2], want) {
+		t.Fatalf("gesture args = %q, want %q", got[2], want)
+	}
+}
+
+func TestDeviceGesture_Invalid(t *testing.T) {
+	c, calls := fakeADBSeq(t,
+		fakeResponse{stdout: geteventDevices},
+		fakeResponse{stdout: "Physical size: 1080x1920\n"},
+	)
+	d := fakeDevice(c, "S", USB)
+	tests := []struct {
+		name string
+		g    Gesture
+	}{
+		{"no pointers", Gesture{}},
+		{"empty path", Gesture{Pointers: [][]Point{{{1, 2}}, nil}}},
+		{"negative hold", Gesture{Pointers: [][]Point{{{1, 2}}}, Hold: -time.Second}},
+		{"no fingers", MultiSwipe(0, Point{0, 0}, Point{0, 100}, 10, 0)},
+		{"negative fingers", MultiSwipe(-1, Point{0, 0}, Point{0, 100}, 10, 0)},
+		{"more pointers than slots", MultiSwipe(11, Point{0, 0}, Point{0, 100}, 10, 0)},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := d.Gesture(context.Background(), tc.g); !errors.Is(err, ErrInvalidGesture) {
+				t.Fatalf("Gesture() error = %v, want ErrInvalidGesture", err)
+			}
+		})
+	}
+	// Only the slot check needs the device.
+	if n := len(calls()); n != 1 {
+		t.Fatalf("calls = %d, want 1", n)
+	}
+}
+
+func TestSequenceGestureEvent(t *testing.T) {
+	g := Pinch(Point{540, 960}, 300, 100, 400*time.Millisecond)
+	seq := NewSequence(Resolution{Width: 1080, Height: 1920},
+		NewTap(1, 2),
+		NewGesture(g),
+	)
+	// The sequence keeps its own copy of the gesture.
+	g.Pointers[0][0] = Point{}
+
+	events := seq.Events()
+	ev := events[1]
+	if ev.Kind != GestureEvent || ev.Duration != 400*time.Millisecond {
+		t.Fatalf("gesture event = %+v", ev)
+	}
+	if want := Pinch(Point{540, 960}, 300, 100, 400*time.Millisecond); !reflect.DeepEqual(*ev.Gesture, want) {
+		t.Fatalf("gesture = %+v, want %+v", *ev.Gesture, want)
+	}
+	if got := seq.Duration(); got != 440*time.Millisecond {
+		t.Fatalf("Duration() = %v, want 440ms", got)
+	}
+
+	data, err := json.Marshal(seq)
+	if err != nil {
+		t.Fatalf("json.Marshal error = %v", err)
+	}
+	parsed, err := ParseSequence(data)
+	if err != nil {
+		t.Fatalf("ParseSequence() error = %v", err)
+	}
+	if !reflect.DeepEqual(parsed.Events(), events) {
+		t.Fatalf("round trip = %+v, want %+v", parsed.Events(), events)
+	}
+}
+
+func TestReplay_Gesture(t *testing.T) {
+	c, argsFile := fakeADB(t, "", "", 0)
+	d := fakeDevice(c, "S", USB)
+	seq := NewSequence(Resolution{}, NewGesture(DragAndDrop(Point{1, 2}, Point{3, 4}, 0, 0)))
+	if err := d.Replay(context.Background(), seq); err != nil {
+		t.Fatalf("Replay() error = %v", err)
+	}
+	want := []string{"-s", "S", "shell", "input", "swipe", "1", "2", "3", "4", "0"}
+	if got := readArgs(t, argsFile); !reflect.DeepEqual(got, want) {
+		t.Fatalf("args = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,178 @@
+package adb
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// GfxInfo is a process's frame rendering statistics, parsed from
+// `dumpsys gfxinfo <pkg> framestats`.
+type GfxInfo struct {
+	// TotalFrames is the number of frames rendered since the stats were last
+	// reset.
+	TotalFrames int
+	// JankyFrames is the number of frames that missed their deadline.
+	JankyFrames int
+	// JankyPercent is JankyFrames as a percentage of TotalFrames.
+	JankyPercent float64
+	// P50, P90, P95, and P99 are frame time percentiles. The device reports
+	// them with millisecond granularity.
+	P50, P90, P95, P99 time.Duration
+	// MissedVsync counts frames delayed by a missed vsync.
+	MissedVsync int
+	// HighInputLatency counts frames delayed by slow input handling.
+	HighInputLatency int
+	// SlowUIThread counts frames delayed by work on the UI thread.
+	SlowUIThread int
+	// SlowBitmapUploads counts frames delayed by bitmap uploads.
+	SlowBitmapUploads int
+	// SlowDrawCommands counts frames delayed by issuing draw commands.
+	SlowDrawCommands int
+	// Frames holds the per-frame timings of the most recent frames (the
+	// framework keeps about 120).
+	Frames []FrameTiming
+}
+
+// FrameTiming is one row of framestats data. Timestamps are CLOCK_MONOTONIC
+// times, measured from device boot; zero means the phase did not happen.
+type FrameTiming struct {
+	// Flags is non-zero for frames that should be excluded from jank
+	// analysis, such as the first frame after a window layout change.
+	Flags                  int64
+	IntendedVsync          time.Duration
+	Vsync                  time.Duration
+	HandleInputStart       time.Duration
+	AnimationStart         time.Duration
+	PerformTraversalsStart time.Duration
+	DrawStart              time.Duration
+	SyncQueued             time.Duration
+	SyncStart              time.Duration
+	IssueDrawCommandsStart time.Duration
+	SwapBuffers            time.Duration
+	FrameCompleted         time.Duration
+}
+
+// Duration returns the frame's total time, from its intended vsync to
+// completion.
+func (f FrameTiming) Duration() time.Duration { return f.FrameCompleted - f.IntendedVsync }
+
+// GfxInfo returns the frame rendering statistics of a package's process,
+// equivalent to `adb shell dumpsys gfxinfo <pkg> framestats`. When reset is
+// set the statistics are reset after they are read, so the next call covers
+// only frames rendered in between; reset before replaying a [Sequence] to
+// measure the jank of just that interaction. It returns an error wrapping
+// [ErrProcessNotFound] if the package is not running.
+func (d Device) GfxInfo(ctx context.Context, pkg string, reset bool) (GfxInfo, error) {
+	args := []string{"-s", d.serial, "shell", "dumpsys", "gfxinfo", pkg, "framestats"}
+	if reset {
+		args = append(args, "reset")
+	}
+	res, err := d.client.run(ctx, args...)
+	if err != nil {
+		return GfxInfo{}, err
+	}
+	g, ok := parseGfxInfo(res.StdoutString())
+	if !ok {
+		return GfxInfo{}, fmt.Errorf("%w: %s", ErrProcessNotFound, pkg)
+	}
+	return g, nil
+}
+
+// parseGfxInfo parses gfxinfo output, reporting false when it contains no
+// process. Newer releases repeat the summary per window after the process
+// totals; only the first (process-wide) value of each statistic is used.
+func parseGfxInfo(out string) (GfxInfo, bool) {
+	var g GfxInfo
+	if !strings.Contains(out, "** Graphics info for pid") {
+		return g, false
+	}
+	seen := map[string]bool{}
+	var header []string // column names while inside a PROFILEDATA block
+	inProfile := false
+	for line := range strings.SplitSeq(out, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "---PROFILEDATA---" {
+			inProfile = !inProfile
+			header = nil
+			continue
+		}
+		if inProfile {
+			if line == "" {
+				continue
+			}
+			if header == nil {
+				header = strings.Split(line, ",")
+				continue
+			}
+			g.Frames = append(g.Frames, parseFrameTiming(header, strings.Split(line, ",")))
+			continue
+		}
+		key, value, ok := strings.Cut(line, ": ")
+		if !ok || seen[key] {
+			continue
+		}
+		seen[key] = true
+		switch key {
+		case "Total frames rendered":
+			g.TotalFrames, _ = strconv.Atoi(value)
+		case "Janky frames":
+			count, pct, _ := strings.Cut(value, " ")
+			g.JankyFrames, _ = strconv.Atoi(count)
+			pct = strings.Trim(pct, "()%")
+			g.JankyPercent, _ = strconv.ParseFloat(pct, 64)
+		case "50th percentile":
+			g.P50 = parseMillis(strings.TrimSuffix(value, "ms"))
+		case "90th percentile":
+			g.P90 = parseMillis(strings.TrimSuffix(value, "ms"))
+		case "95th percentile":
+			g.P95 = parseMillis(strings.TrimSuffix(value, "ms"))
+		case "99th percentile":
+			g.P99 = parseMillis(strings.TrimSuffix(value, "ms"))
+		case "Number Missed Vsync":
+			g.MissedVsync, _ = strconv.Atoi(value)
+		case "Number High input latency":
+			g.HighInputLatency, _ = strconv.Atoi(value)
+		case "Number Slow UI thread":
+			g.SlowUIThread, _ = strconv.Atoi(value)
+		case "Number Slow bitmap uploads":
+			g.SlowBitmapUploads, _ = strconv.Atoi(value)
+		case "Number Slow issue draw commands":
+			g.SlowDrawCommands, _ = strconv.Atoi(value)
+		}
+	}
+	return g, true
+}
+
+// parseFrameTiming maps a framestats row onto its named columns. Columns are
+// looked up by name because releases add and reorder them.
+func parseFrameTiming(header, row []string) FrameTiming {
+	var f FrameTiming
+	fields := map[string]*time.Duration{
+		"IntendedVsync":          &f.IntendedVsync,
+		"Vsync":                  &f.Vsync,
+		"HandleInputStart":       &f.HandleInputStart,
+		"AnimationStart":         &f.AnimationStart,
+		"PerformTraversalsStart": &f.PerformTraversalsStart,
+		"DrawStart":              &f.DrawStart,
+		"SyncQueued":             &f.SyncQueued,
+		"SyncStart":              &f.SyncStart,
+		"IssueDrawCommandsStart": &f.IssueDrawCommandsStart,
+		"SwapBuffers":            &f.SwapBuffers,
+		"FrameCompleted":         &f.FrameCompleted,
+	}
+	for i, name := range header {
+		if i >= len(row) {
+			break
+		}
+		n, _ := strconv.ParseInt(strings.TrimSpace(row[i]), 10, 64)
+		if name == "Flags" {
+			f.Flags = n
+		} else if p, ok := fields[name]; ok {
+			*p = time.Duration(n)
+		}
+	}
+	return f
+}
@@ -0,0 +1,87 @@
+package adb
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+const gfxInfoDump = `Applications Graphics Acceleration Info:
+Uptime: 5918117 Realtime: 5918117
+
+** Graphics info for pid 4321 [com.example] **
+
+Stats since: 5886233530545ns
+Total frames rendered: 120
+Janky frames: 12 (10.00%)
+Janky frames (legacy): 10 (8.33%)
+50th percentile: 8ms
+90th percentile: 16ms
+95th percentile: 21ms
+99th percentile: 42ms
+Number Missed Vsync: 2
+Number High input latency: 1
+Number Slow UI thread: 5
+Number Slow bitmap uploads: 0
+Number Slow issue draw commands: 3
+Number Frame deadline missed: 10
+HISTOGRAM: 5ms=50 6ms=20 7ms=10
+
+Window: com.example/com.example.MainActivity
+Stats since: 5886233530545ns
+Total frames rendered: 60
+Janky frames: 1 (1.67%)
+
+---PROFILEDATA---
+Flags,FrameTimelineVsyncId,IntendedVsync,Vsync,InputEventId,HandleInputStart,AnimationStart,PerformTraversalsStart,DrawStart,FrameDeadline,FrameInterval,FrameStartTime,SyncQueued,SyncStart,IssueDrawCommandsStart,SwapBuffers,FrameCompleted,DequeueBufferDuration,QueueBufferDuration,GpuCompleted,SwapBuffersCompleted,DisplayPresentTime,
+1,100,1000000,1000100,0,1000200,1000300,1000400,1000500,17666666,16666666,1000000,1000600,1000700,1000800,1000900,5000000,10,20,4000000,4500000,0,
+0,101,17666666,17666700,0,17666800,17666900,17667000,17667100,34333332,16666666,17666666,17667200,17667300,17667400,17667500,29666666,10,20,0,0,0,
+---PROFILEDATA---
+
+View hierarchy:
+`
+
+func TestGfxInfo(t *testing.T) {
+	c, argsFile := fakeADB(t, gfxInfoDump, "", 0)
+	got, err := c.Device("S").GfxInfo(context.Background(), "com.example", true)
+	if err != nil {
+		t.Fatalf("GfxInfo() error = %v", err)
+	}
+	wantArgs := []string{"-s", "S", "shell", "dumpsys", "gfxinfo", "com.example", "framestats", "reset"}
+	if args := readArgs(t, argsFile); !reflect.DeepEqual(args, wantArgs) {
+		t.Fatalf("GfxInfo() args = %v, want %v", args, wantArgs)
+	}
+	if got.TotalFrames != 120 || got.JankyFrames != 12 || got.JankyPercent != 10 {
+		t.Fatalf("GfxInfo() totals = %d %d %v", got.TotalFrames, got.JankyFrames, got.JankyPercent)
+	}
+	if got.P50 != 8*time.Millisecond || got.P90 != 16*time.Millisecond ||
+		got.P95 != 21*time.Millisecond || got.P99 != 42*time.Millisecond {
+		t.Fatalf("GfxInfo() percentiles = %v %v %v %v", got.P50, got.P90, got.P95, got.P99)
+	}
+	if got.MissedVsync != 2 || got.HighInputLatency != 1 || got.SlowUIThread != 5 || got.SlowDrawCommands != 3 {
+		t.Fatalf("GfxInfo() counters = %+v", got)
+	}
+	if len(got.Frames) != 2 {
+		t.Fatalf("GfxInfo() parsed %d frames, want 2", len(got.Frames))
+	}
+	f := got.Frames[1]
+	if f.Flags != 0 || f.IntendedVsync != 17666666 || f.SwapBuffers != 17667500 || f.Duration() != 12*time.Millisecond {
+		t.Fatalf("GfxInfo() frame = %+v (duration %v)", f, f.Duration())
+	}
+	if got.Frames[0].Flags != 1 {
+		t.Fatalf("GfxInfo() first frame flags = %d, want 1", got.Frames[0].Flags)
+	}
+}
+
+func TestGfxInfo_NotRunning(t *testing.T) {
+	c, argsFile := fakeADB(t, "Applications Graphics Acceleration Info:\nUptime: 1 Realtime: 1\nNo process found for: com.example\n", "", 0)
+	if _, err := c.Device("S").GfxInfo(context.Background(), "com.example", false); !errors.Is(err, ErrProcessNotFound) {
+		t.Fatalf("GfxInfo() error = %v, want ErrProcessNotFound", err)
+	}
+	wantArgs := []string{"-s", "S", "shell", "dumpsys", "gfxinfo", "com.example", "framestats"}
+	if args := readArgs(t, argsFile); !reflect.DeepEqual(args, wantArgs) {
+		t.Fatalf("GfxInfo() args = %v, want %v", args, wantArgs)
+	}
+}
@@ -0,0 +1,83 @@
+package adb
+
+import (
+	"context"
+	"strconv"
+)
+
+// InputSource is the class of input device that `input` injects events as.
+type InputSource string
+
+const (
+	// InputDefault lets `input` pick the source for each command: a
+	// touchscreen for taps and swipes, a keyboard for keys and text.
+	InputDefault InputSource = ""
+	// InputTouchscreen injects events from a touchscreen.
+	InputTouchscreen InputSource = "touchscreen"
+	// InputTouchpad injects events from a touchpad.
+	InputTouchpad InputSource = "touchpad"
+	// InputMouse injects events from a mouse.
+	InputMouse InputSource = "mouse"
+	// InputStylus injects events from a stylus.
+	InputStylus InputSource = "stylus"
+	// InputDPad injects events from a directional pad.
+	InputDPad InputSource = "dpad"
+	// InputKeyboard injects events from a keyboard.
+	InputKeyboard InputSource = "keyboard"
+	// InputGamepad injects events from a gamepad.
+	InputGamepad InputSource = "gamepad"
+	// InputTrackball injects events from a trackball.
+	InputTrackball InputSource = "trackball"
+)
+
+// inputTarget is the source and display that a device's input commands
+// address.
+type inputTarget struct {
+	source     InputSource
+	display    int
+	hasDisplay bool
+}
+
+// WithInputSource returns a handle to the same device whose input commands
+// ([Device.Tap], [Device.Swipe], [Device.KeyEvent], [Device.InputText], and
+// the like) inject events as src, equivalent to `adb shell input <src> ...`.
+// Use it to exercise stylus- or mouse-specific code paths.
+func (d Device) WithInputSource(src InputSource) Device {
+	d.target.source = src
+	return d
+}
+
+// OnDisplay returns a handle to the same device whose input commands target
+// the display with the given id, equivalent to `adb shell input -d <id> ...`
+// (Android 10 or later). Use it to drive secondary displays such as an
+// Android Auto projection; display 0 is the built-in screen.
+func (d Device) OnDisplay(id int) Device {
+	d.target.display, d.target.hasDisplay = id, true
+	return d
+}
+
+// InputSource returns the source set with [Device.WithInputSource].
+func (d Device) InputSource() InputSource { return d.target.source }
+
+// Display returns the display set with [Device.OnDisplay]. The boolean is
+// false when input goes to the default display.
+func (d Device) Display() (int, bool) { return d.target.display, d.target.hasDisplay }
+
+// inputArgs returns the argv of an `input <cmd> <args>...` command addressed
+// to the device's input source and display.
+func (d Device) inputArgs(cmd string, args ...string) []string {
+	argv := []string{"input"}
+	if d.target.source != InputDefault {
+		argv = append(argv, string(d.target.source))
+	}
+	if d.target.hasDisplay {
+		argv = append(argv, "-d", strconv.Itoa(d.target.display))
+	}
+	return append(append(argv, cmd), args...)
+}
+
+// input runs `adb shell input <cmd> <args>...` against the device's input
+// source and display.
+func (d Device) input(ctx context.Context, cmd string, args ...string) error {
+	return d.exec(ctx, append([]string{"shell"}, d.inputArgs(cmd, args...)...)...)
+}
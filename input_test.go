@@ -0,0 +1,145 @@
+package adb
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestInputTargetArgs(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name string
+		call func(Device) error
+		want []string
+	}{
+		{
+			name: "stylus tap",
+			call: func(d Device) error { return d.WithInputSource(InputStylus).Tap(ctx, 10, 20) },
+			want: []string{"-s", "S", "shell", "input", "stylus", "tap", "10", "20"},
+		},
+		{
+			name: "swipe on display",
+			call: func(d Device) error { return d.OnDisplay(2).Swipe(ctx, 1, 2, 3, 4, time.Second) },
+			want: []string{"-s", "S", "shell", "input", "-d", "2", "swipe", "1", "2", "3", "4", "1000"},
+		},
+		{
+			name: "dpad keyevent on display",
+			call: func(d Device) error {
+				return d.WithInputSource(InputDPad).OnDisplay(3).KeyEvent(ctx, KeycodeDPadCenter)
+			},
+			want: []string{"-s", "S", "shell", "input", "dpad", "-d", "3", "keyevent", "23"},
+		},
+		{
+			name: "keyboard text",
+			call: func(d Device) error { return d.WithInputSource(InputKeyboard).InputText(ctx, "hi") },
+			want: []string{"-s", "S", "shell", "input", "keyboard", "text", "hi"},
+		},
+		{
+			name: "default display",
+			call: func(d Device) error { return d.OnDisplay(0).Tap(ctx, 1, 2) },
+			want: []string{"-s", "S", "shell", "input", "-d", "0", "tap", "1", "2"},
+		},
+		{
+			name: "reset source",
+			call: func(d Device) error {
+				return d.WithInputSource(InputMouse).WithInputSource(InputDefault).Tap(ctx, 1, 2)
+			},
+			want: []string{"-s", "S", "shell", "input", "tap", "1", "2"},
+		},
+		{
+			name: "swipe gesture",
+			call: func(d Device) error {
+				return d.WithInputSource(InputMouse).OnDisplay(1).Gesture(ctx, DragAndDrop(Point{1, 2}, Point{3, 4}, 0, 0))
+			},
+			want: []string{"-s", "S", "shell", "input", "mouse", "-d", "1", "swipe", "1", "2", "3", "4", "0"},
+		},
+		{
+			name: "motionevent gesture",
+			call: func(d Device) error {
+				return d.WithInputSource(InputMouse).OnDisplay(1).Gesture(ctx, DragAndDrop(Point{1, 2}, Point{3, 4}, time.Second, 0))
+			},
+			want: []string{"-s", "S", "shell", "set -e;" +
+				"input mouse -d 1 motionevent DOWN 1 2;sleep 1;" +
+				"input mouse -d 1 motionevent MOVE 3 4;" +
+				"input mouse -d 1 motionevent UP 3 4;"},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			c, argsFile := fakeADB(t, "", "", 0)
+			if err := tc.call(fakeDevice(c, "S", USB)); err != nil {
+				t.Fatalf("%s error = %v", tc.name, err)
+			}
+			if got := readArgs(t, argsFile); !reflect.DeepEqual(got, tc.want) {
+				t.Fatalf("args = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestInputTargetAccessors(t *testing.T) {
+	d := fakeDevice(nil, "S", USB)
+	if _, ok := d.Display(); ok || d.InputSource() != InputDefault {
+		t.Fatalf("zero device targets %v, %q", ok, d.InputSource())
+	}
+	targeted := d.WithInputSource(InputGamepad).OnDisplay(4)
+	if id, ok := targeted.Display(); !ok || id != 4 || targeted.InputSource() != InputGamepad {
+		t.Fatalf("Display() = %d, %v; InputSource() = %q", id, ok, targeted.InputSource())
+	}
+	// The original handle is unchanged.
+	if _, ok := d.Display(); ok {
+		t.Fatal("OnDisplay modified the receiver")
+	}
+}
+
+func TestGesture_MultiPointerOnDisplay(t *testing.T) {
+	c, calls := fakeADBSeq(t, fakeResponse{})
+	err := fakeDevice(c, "S", USB).OnDisplay(2).Gesture(context.Background(), Pinch(Point{100, 100}, 50, 10, 0))
+	if !errors.Is(err, ErrInvalidGesture) {
+		t.Fatalf("Gesture() error = %v, want ErrInvalidGesture", err)
+	}
+	if n := len(calls()); n != 0 {
+		t.Fatalf("calls = %d, want 0", n)
+	}
+}
+
+func TestSequenceDisplay(t *testing.T) {
+	seq := NewSequence(Resolution{Width: 1920, Height: 1080}, NewTap(5, 6))
+	if _, ok := seq.Display(); ok {
+		t.Fatal("new sequence has a display")
+	}
+	seq = seq.OnDisplay(2).ShortenSleeps(2)
+	if id, ok := seq.Display(); !ok || id != 2 {
+		t.Fatalf("Display() = %d, %v; want 2, true", id, ok)
+	}
+
+	data, err := json.Marshal(seq)
+	if err != nil {
+		t.Fatalf("json.Marshal error = %v", err)
+	}
+	parsed, err := ParseSequence(data)
+	if err != nil {
+		t.Fatalf("ParseSequence() error = %v", err)
+	}
+	if id, ok := parsed.Display(); !ok || id != 2 {
+		t.Fatalf("parsed Display() = %d, %v; want 2, true", id, ok)
+	}
+	// Display 0 is not the same as no display.
+	data, _ = json.Marshal(seq.OnDisplay(0))
+	if parsed, _ := ParseSequence(data); !reflect.DeepEqual(parsed, seq.OnDisplay(0)) {
+		t.Fatalf("display 0 round trip = %+v", parsed)
+	}
+
+	c, argsFile := fakeADB(t, "", "", 0)
+	if err := fakeDevice(c, "S", USB).OnDisplay(7).Replay(context.Background(), parsed); err != nil {
+		t.Fatalf("Replay() error = %v", err)
+	}
+	want := []string{"-s", "S", "shell", "input", "-d", "2", "tap", "5", "6"}
+	if got := readArgs(t, argsFile); !reflect.DeepEqual(got, want) {
+		t.Fatalf("args = %q, want %q", got, want)
+	}
+}
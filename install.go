@@ -0,0 +1,166 @@
+package adb
+
+import (
+	"context"
+	"os"
+)
+
+// StreamingMode selects how adb transfers APKs to the device.
+type StreamingMode int
+
+const (
+	// StreamingDefault lets adb choose (streaming when the device supports it).
+	StreamingDefault StreamingMode = iota
+	// StreamingOn forces streamed installs (--streaming).
+	StreamingOn
+	// StreamingOff pushes the APK to the device before installing
+	// (--no-streaming).
+	StreamingOff
+)
+
+// InstallOptions configures [Device.InstallWithOptions],
+// [Device.InstallMultiple], and [Device.InstallMultiPackage]. The zero value
+// performs a plain install.
+type InstallOptions struct {
+	// Replace reinstalls an existing app, keeping its data (-r).
+	Replace bool
+	// Downgrade allows installing a lower version code (-d).
+	Downgrade bool
+	// GrantAll grants every runtime permission in the manifest (-g).
+	GrantAll bool
+	// Test allows test-only APKs (-t).
+	Test bool
+	// Instant installs the app as an instant app (--instant).
+	Instant bool
+	// User installs for the given user (--user): a user id, "current", or
+	// "all". Empty uses the pm default.
+	User string
+	// ABI overrides the ABI the app is installed for (--abi).
+	ABI string
+	// FastDeploy only pushes the parts of the APK that changed (--fastdeploy).
+	FastDeploy bool
+	// Streaming selects streamed or pushed installs.
+	Streaming StreamingMode
+}
+
+func (o InstallOptions) args() []string {
+	var args []string
+	for _, f := range []struct {
+		set  bool
+		flag string
+	}{
+		{o.Replace, "-r"},
+		{o.Downgrade, "-d"},
+		{o.GrantAll, "-g"},
+		{o.Test, "-t"},
+		{o.Instant, "--instant"},
+		{o.FastDeploy, "--fastdeploy"},
+		{o.Streaming == StreamingOn, "--streaming"},
+		{o.Streaming == StreamingOff, "--no-streaming"},
+	} {
+		if f.set {
+			args = append(args, f.flag)
+		}
+	}
+	if o.User != "" {
+		args = append(args, "--user", shellQuote(o.User))
+	}
+	if o.ABI != "" {
+		args = append(args, "--abi", shellQuote(o.ABI))
+	}
+	return args
+}
+
+// InstallWithOptions pushes and installs an APK, equivalent to
+// `adb install [options] <apk>`.
+func (d Device) InstallWithOptions(ctx context.Context, apkPath string, opts InstallOptions) error {
+	return d.install(ctx, "install", opts, apkPath)
+}
+
+// InstallMultiple installs a single app made of several APKs (a base APK and
+// its splits, as produced from an app bundle), equivalent to
+// `adb install-multiple`.
+func (d Device) InstallMultiple(ctx context.Context, apkPaths []string, opts InstallOptions) error {
+	return d.install(ctx, "install-multiple", opts, apkPaths...)
+}
+
+// InstallMultiPackage atomically installs several apps, one APK each,
+// equivalent to `adb install-multi-package`. Either every app is installed or
+// none is.
+func (d Device) InstallMultiPackage(ctx context.Context, apkPaths []string, opts InstallOptions) error {
+	return d.install(ctx, "install-multi-package", opts, apkPaths...)
+}
+
+func (d Device) install(ctx context.Context, command string, opts InstallOptions, apkPaths ...string) error {
+	if len(apkPaths) == 0 {
+		return ErrNoAPKs
+	}
+	for _, p := range apkPaths {
+		if _, err := os.Stat(p); err != nil {
+			return err
+		}
+	}
+	args := append([]string{command}, opts.args()...)
+	return d.execChecked(ctx, append(args, apkPaths...)...)
+}
+
+// InstallFailureReason is a package-manager install failure code, as reported
+// in `Failure [CODE]` output and carried by [InstallError]. Codes not listed
+// below are preserved verbatim.
+type InstallFailureReason string
+
+// Install failure codes, from android.content.pm.PackageManager.
+const (
+	InstallFailedAlreadyExists                 InstallFailureReason = "INSTALL_FAILED_ALREADY_EXISTS"
+	InstallFailedInvalidAPK                    InstallFailureReason = "INSTALL_FAILED_INVALID_APK"
+	InstallFailedInvalidURI                    InstallFailureReason = "INSTALL_FAILED_INVALID_URI"
+	InstallFailedInsufficientStorage           InstallFailureReason = "INSTALL_FAILED_INSUFFICIENT_STORAGE"
+	InstallFailedDuplicatePackage              InstallFailureReason = "INSTALL_FAILED_DUPLICATE_PACKAGE"
+	InstallFailedNoSharedUser                  InstallFailureReason = "INSTALL_FAILED_NO_SHARED_USER"
+	InstallFailedUpdateIncompatible            InstallFailureReason = "INSTALL_FAILED_UPDATE_INCOMPATIBLE"
+	InstallFailedSharedUserIncompatible        InstallFailureReason = "INSTALL_FAILED_SHARED_USER_INCOMPATIBLE"
+	InstallFailedMissingSharedLibrary          InstallFailureReason = "INSTALL_FAILED_MISSING_SHARED_LIBRARY"
+	InstallFailedReplaceCouldntDelete          InstallFailureReason = "INSTALL_FAILED_REPLACE_COULDNT_DELETE"
+	InstallFailedDexopt                        InstallFailureReason = "INSTALL_FAILED_DEXOPT"
+	InstallFailedOlderSDK                      InstallFailureReason = "INSTALL_FAILED_OLDER_SDK"
+	InstallFailedConflictingProvider           InstallFailureReason = "INSTALL_FAILED_CONFLICTING_PROVIDER"
+	InstallFailedNewerSDK                      InstallFailureReason = "INSTALL_FAILED_NEWER_SDK"
+	InstallFailedTestOnly                      InstallFailureReason = "INSTALL_FAILED_TEST_ONLY"
+	InstallFailedCPUABIIncompatible            InstallFailureReason = "INSTALL_FAILED_CPU_ABI_INCOMPATIBLE"
+	InstallFailedMissingFeature                InstallFailureReason = "INSTALL_FAILED_MISSING_FEATURE"
+	InstallFailedContainerError                InstallFailureReason = "INSTALL_FAILED_CONTAINER_ERROR"
+	InstallFailedInvalidInstallLocation        InstallFailureReason = "INSTALL_FAILED_INVALID_INSTALL_LOCATION"
+	InstallFailedMediaUnavailable              InstallFailureReason = "INSTALL_FAILED_MEDIA_UNAVAILABLE"
+	InstallFailedVerificationTimeout           InstallFailureReason = "INSTALL_FAILED_VERIFICATION_TIMEOUT"
+	InstallFailedVerificationFailure           InstallFailureReason = "INSTALL_FAILED_VERIFICATION_FAILURE"
+	InstallFailedPackageChanged                InstallFailureReason = "INSTALL_FAILED_PACKAGE_CHANGED"
+	InstallFailedUIDChanged                    InstallFailureReason = "INSTALL_FAILED_UID_CHANGED"
+	InstallFailedVersionDowngrade              InstallFailureReason = "INSTALL_FAILED_VERSION_DOWNGRADE"
+	InstallFailedPermissionModelDowngrade      InstallFailureReason = "INSTALL_FAILED_PERMISSION_MODEL_DOWNGRADE"
+	InstallFailedNoMatchingABIs                InstallFailureReason = "INSTALL_FAILED_NO_MATCHING_ABIS"
+	InstallFailedAborted                       InstallFailureReason = "INSTALL_FAILED_ABORTED"
+	InstallFailedInternalError                 InstallFailureReason = "INSTALL_FAILED_INTERNAL_ERROR"
+	InstallFailedUserRestricted                InstallFailureReason = "INSTALL_FAILED_USER_RESTRICTED"
+	InstallFailedDuplicatePermission           InstallFailureReason = "INSTALL_FAILED_DUPLICATE_PERMISSION"
+	InstallFailedMissingSplit                  InstallFailureReason = "INSTALL_FAILED_MISSING_SPLIT"
+	InstallFailedDeprecatedSDKVersion          InstallFailureReason = "INSTALL_FAILED_DEPRECATED_SDK_VERSION"
+	InstallParseFailedNotAPK                   InstallFailureReason = "INSTALL_PARSE_FAILED_NOT_APK"
+	InstallParseFailedBadManifest              InstallFailureReason = "INSTALL_PARSE_FAILED_BAD_MANIFEST"
+	InstallParseFailedNoCertificates           InstallFailureReason = "INSTALL_PARSE_FAILED_NO_CERTIFICATES"
+	InstallParseFailedInconsistentCerts        InstallFailureReason = "INSTALL_PARSE_FAILED_INCONSISTENT_CERTIFICATES"
+	InstallParseFailedManifestMalformed        InstallFailureReason = "INSTALL_PARSE_FAILED_MANIFEST_MALFORMED"
+	InstallParseFailedManifestEmpty            InstallFailureReason = "INSTALL_PARSE_FAILED_MANIFEST_EMPTY"
+	InstallParseFailedBadPackageName           InstallFailureReason = "INSTALL_PARSE_FAILED_BAD_PACKAGE_NAME"
+	InstallParseFailedBadSharedUserID          InstallFailureReason = "INSTALL_PARSE_FAILED_BAD_SHARED_USER_ID"
+	InstallParseFailedUnexpectedException      InstallFailureReason = "INSTALL_PARSE_FAILED_UNEXPECTED_EXCEPTION"
+	InstallParseFailedCertificateEncoding      InstallFailureReason = "INSTALL_PARSE_FAILED_CERTIFICATE_ENCODING"
+	InstallFailedSessionInvalid                InstallFailureReason = "INSTALL_FAILED_SESSION_INVALID"
+	InstallFailedMultiPackageInconsistency     InstallFailureReason = "INSTALL_FAILED_MULTI_PACKAGE_INCONSISTENCY"
+	InstallFailedWrongInstalledVersion         InstallFailureReason = "INSTALL_FAILED_WRONG_INSTALLED_VERSION"
+	InstallFailedProcessNotDefined             InstallFailureReason = "INSTALL_FAILED_PROCESS_NOT_DEFINED"
+	InstallFailedInstantAppInvalid             InstallFailureReason = "INSTALL_FAILED_INSTANT_APP_INVALID"
+	InstallFailedBadDexMetadata                InstallFailureReason = "INSTALL_FAILED_BAD_DEX_METADATA"
+	InstallFailedBadSignature                  InstallFailureReason = "INSTALL_FAILED_BAD_SIGNATURE"
+	InstallFailedOtherStagedSessionInProgress  InstallFailureReason = "INSTALL_FAILED_OTHER_STAGED_SESSION_IN_PROGRESS"
+	InstallFailedMultiArchNotMatchAllNativeABI InstallFailureReason = "INSTALL_FAILED_MULTIARCH_NOT_MATCH_ALL_NATIVE_ABI"
+)
@@ -0,0 +1,162 @@
+package adb
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// writeAPKs creates placeholder APK files and returns their paths.
+func writeAPKs(t *testing.T, names ...string) []string {
+	t.Helper()
+	dir := t.TempDir()
+	paths := make([]string, len(names))
+	for i, name := range names {
+		paths[i] = filepath.Join(dir, name)
+		if err := os.WriteFile(paths[i], []byte("dex"), 0o600); err != nil {
+			t.Fatalf("write apk: %v", err)
+		}
+	}
+	return paths
+}
+
+func TestInstallOptionsArgs(t *testing.T) {
+	apks := writeAPKs(t, "base.apk", "split_config.arm64_v8a.apk")
+	opts := InstallOptions{
+		Replace: true, Downgrade: true, GrantAll: true, Test: true, Instant: true,
+		FastDeploy: true, Streaming: StreamingOff, User: "10", ABI: "arm64-v8a",
+	}
+	tests := []struct {
+		name string
+		call func(Device) error
+		want []string
+	}{
+		{
+			name: "single",
+			call: func(d Device) error { return d.InstallWithOptions(context.Background(), apks[0], opts) },
+			want: []string{"-s", "S", "install", "-r", "-d", "-g", "-t", "--instant", "--fastdeploy", "--no-streaming", "--user", "10", "--abi", "arm64-v8a", apks[0]},
+		},
+		{
+			name: "multiple",
+			call: func(d Device) error {
+				return d.InstallMultiple(context.Background(), apks, InstallOptions{Streaming: StreamingOn})
+			},
+			want: []string{"-s", "S", "install-multiple", "--streaming", apks[0], apks[1]},
+		},
+		{
+			name: "multi-package",
+			call: func(d Device) error { return d.InstallMultiPackage(context.Background(), apks, InstallOptions{}) },
+			want: []string{"-s", "S", "install-multi-package", apks[0], apks[1]},
+		},
+		{
+			name: "quoted values",
+			call: func(d Device) error {
+				return d.InstallWithOptions(context.Background(), apks[0], InstallOptions{User: "0;reboot", ABI: "arm64 x86"})
+			},
+			want: []string{"-s", "S", "install", "--user", "'0;reboot'", "--abi", "'arm64 x86'", apks[0]},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, argsFile := fakeADB(t, "Success\n", "", 0)
+			if err := tt.call(c.Device("S")); err != nil {
+				t.Fatalf("%s error = %v", tt.name, err)
+			}
+			if got := readArgs(t, argsFile); !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("args = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInstallMultiple_Validation(t *testing.T) {
+	c, _ := fakeADB(t, "Success\n", "", 0)
+	d := c.Device("S")
+	if err := d.InstallMultiple(context.Background(), nil, InstallOptions{}); !errors.Is(err, ErrNoAPKs) {
+		t.Fatalf("InstallMultiple() with no APKs error = %v, want ErrNoAPKs", err)
+	}
+	apks := append(writeAPKs(t, "base.apk"), filepath.Join(t.TempDir(), "missing.apk"))
+	if err := d.InstallMultiple(context.Background(), apks, InstallOptions{}); !os.IsNotExist(err) {
+		t.Fatalf("InstallMultiple() error = %v, want not-exist", err)
+	}
+}
+
+func TestInstall_TypedFailure(t *testing.T) {
+	apk := writeAPKs(t, "app.apk")[0]
+	tests := []struct {
+		name   string
+		stdout string
+		stderr string
+		code   int
+		reason InstallFailureReason
+		detail string
+	}{
+		{
+			name:   "exit zero on stdout",
+			stdout: "Performing Streamed Install\nFailure [INSTALL_FAILED_VERSION_DOWNGRADE]\n",
+			reason: InstallFailedVersionDowngrade,
+		},
+		{
+			name:   "non-zero exit on stderr with detail",
+			stderr: "adb: failed to install " + apk + ": Failure [INSTALL_FAILED_UPDATE_INCOMPATIBLE: Package com.example signatures do not match previously installed version; ignoring!]\n",
+			code:   1,
+			reason: InstallFailedUpdateIncompatible,
+			detail: "Package com.example signatures do not match previously installed version; ignoring!",
+		},
+		{
+			name:   "unlisted code preserved",
+			stdout: "Failure [INSTALL_FAILED_SOMETHING_NEW: [details]]\n",
+			reason: "INSTALL_FAILED_SOMETHING_NEW",
+			detail: "[details]",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := fakeADB(t, tt.stdout, tt.stderr, tt.code)
+			err := c.Device("S").Install(context.Background(), apk, true)
+			if !errors.Is(err, ErrCommandFailed) {
+				t.Fatalf("Install() error = %v, want ErrCommandFailed", err)
+			}
+			if !errors.Is(err, &InstallError{Reason: tt.reason}) {
+				t.Fatalf("Install() error = %v, want reason %s", err, tt.reason)
+			}
+			installErr, ok := errors.AsType[*InstallError](err)
+			if !ok {
+				t.Fatalf("Install() error not an *InstallError: %v", err)
+			}
+			if installErr.Detail != tt.detail {
+				t.Fatalf("InstallError.Detail = %q, want %q", installErr.Detail, tt.detail)
+			}
+			if _, ok := errors.AsType[*CommandError](err); !ok {
+				t.Fatalf("Install() error not wrapped in *CommandError: %v", err)
+			}
+		})
+	}
+}
+
+func TestInstallFailure_Anchored(t *testing.T) {
+	tests := []struct {
+		line string
+		want bool
+	}{
+		{"Failure [INSTALL_FAILED_INVALID_APK]", true},
+		{"adb: failed to install /tmp/a: b.apk: Failure [INSTALL_FAILED_OLDER_SDK: needs 34]", true},
+		{"I/Installer: previous attempt: Failure [INSTALL_FAILED_INVALID_APK]", false},
+		{"package:/data/app/Failure [INSTALL_X]/base.apk", false},
+	}
+	for _, tt := range tests {
+		if got := installFailure(tt.line) != nil; got != tt.want {
+			t.Fatalf("installFailure(%q) matched = %v, want %v", tt.line, got, tt.want)
+		}
+	}
+}
+
+func TestInstallError_IsOtherReason(t *testing.T) {
+	err := &InstallError{Reason: InstallFailedOlderSDK}
+	if errors.Is(err, &InstallError{Reason: InstallFailedNoMatchingABIs}) {
+		t.Fatal("InstallError matched a different reason")
+	}
+}
@@ -0,0 +1,229 @@
+package adb
+
+import (
+	"context"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Common intent flags for [Intent.Flags], from android.content.Intent.
+const (
+	FlagActivityNoHistory      = 0x40000000
+	FlagActivitySingleTop      = 0x20000000
+	FlagActivityNewTask        = 0x10000000
+	FlagActivityMultipleTask   = 0x08000000
+	FlagActivityClearTop       = 0x04000000
+	FlagActivityNoAnimation    = 0x00010000
+	FlagActivityReorderToFront = 0x00020000
+	FlagActivityClearTask      = 0x00008000
+	FlagIncludeStoppedPackages = 0x00000020
+	FlagReceiverForeground     = 0x10000000
+)
+
+// Intent describes an intent for the on-device `am` tool. The zero value is an
+// empty intent; set the fields that apply and add extras with the With*
+// methods, which return a modified copy:
+//
+//	intent := adb.Intent{Action: "android.intent.action.VIEW", Data: "https://example.com"}.
+//		WithString("referrer", "adb").
+//		WithBool("debug", true)
+//
+// All values are quoted for the device shell, so they may contain spaces and
+// shell metacharacters.
+type Intent struct {
+	// Action is the intent action (-a), for example
+	// "android.intent.action.VIEW".
+	Action string
+	// Data is the data URI (-d).
+	Data string
+	// MIMEType is the MIME type (-t).
+	MIMEType string
+	// Categories are the intent categories (-c).
+	Categories []string
+	// Component is an explicit component (-n), for example
+	// "com.example/.MainActivity".
+	Component string
+	// Package restricts resolution to a single package (-p).
+	Package string
+	// Flags is a bitmask of intent flags (-f), such as [FlagActivityNewTask].
+	Flags uint32
+	// User is the user to run as (--user): a user id, "current", or "all".
+	// Empty uses the am default.
+	User string
+
+	extras []intentExtra
+}
+
+type intentExtra struct {
+	flag, key, value string
+}
+
+func (i Intent) with(flag, key, value string) Intent {
+	// Clip so appending never writes into an array shared with the receiver.
+	i.extras = append(slices.Clip(i.extras), intentExtra{flag: flag, key: key, value: value})
+	return i
+}
+
+// WithString returns a copy of the intent with a string extra (--es).
+func (i Intent) WithString(key, value string) Intent { return i.with("--es", key, value) }
+
+// WithInt returns a copy of the intent with an int extra (--ei).
+func (i Intent) WithInt(key string, value int32) Intent {
+	return i.with("--ei", key, strconv.FormatInt(int64(value), 10))
+}
+
+// WithLong returns a copy of the intent with a long extra (--el).
+func (i Intent) WithLong(key string, value int64) Intent {
+	return i.with("--el", key, strconv.FormatInt(value, 10))
+}
+
+// WithFloat returns a copy of the intent with a float extra (--ef).
+func (i Intent) WithFloat(key string, value float32) Intent {
+	return i.with("--ef", key, strconv.FormatFloat(float64(value), 'g', -1, 32))
+}
+
+// WithBool returns a copy of the intent with a boolean extra (--ez).
+func (i Intent) WithBool(key string, value bool) Intent {
+	return i.with("--ez", key, strconv.FormatBool(value))
+}
+
+// WithURI returns a copy of the intent with a URI extra (--eu).
+func (i Intent) WithURI(key, uri string) Intent { return i.with("--eu", key, uri) }
+
+// WithStringArray returns a copy of the intent with a string array extra
+// (--esa). Commas inside values are escaped with a backslash so am does not
+// split the value there, but am keeps the backslash, so the receiver sees
+// "a\,b" for the value "a,b".
+func (i Intent) WithStringArray(key string, values ...string) Intent {
+	escaped := make([]string, len(values))
+	for j, v := range values {
+		escaped[j] = strings.ReplaceAll(v, ",", `\,`)
+	}
+	return i.with("--esa", key, strings.Join(escaped, ","))
+}
+
+// args renders the intent as `am` arguments, quoted for the device shell. The
+// User field is not included; it is an `am` option rendered by the caller.
+func (i Intent) args() []string {
+	var args []string
+	add := func(flag, value string) {
+		if value != "" {
+			args = append(args, flag, shellQuote(value))
+		}
+	}
+	add("-a", i.Action)
+	add("-d", i.Data)
+	add("-t", i.MIMEType)
+	for _, c := range i.Categories {
+		add("-c", c)
+	}
+	add("-n", i.Component)
+	add("-p", i.Package)
+	if i.Flags != 0 {
+		args = append(args, "-f", "0x"+strconv.FormatUint(uint64(i.Flags), 16))
+	}
+	for _, e := range i.extras {
+		args = append(args, e.flag, shellQuote(e.key), shellQuote(e.value))
+	}
+	return args
+}
+
+// amArgs builds `shell am <command> [options] [--user U] <intent>`.
+func (i Intent) amArgs(command string, options ...string) []string {
+	args := append([]string{"shell", "am", command}, options...)
+	if i.User != "" {
+		args = append(args, "--user", shellQuote(i.User))
+	}
+	return append(args, i.args()...)
+}
+
+// StartOptions configures [Device.StartIntent].
+type StartOptions struct {
+	// Wait blocks until the launch completes (-W) and populates the launch
+	// timings in [StartResult].
+	Wait bool
+	// ForceStop force-stops the target app before starting the activity (-S).
+	ForceStop bool
+}
+
+// StartResult reports the outcome of an activity launch. The fields are only
+// populated when [StartOptions.Wait] is set.
+type StartResult struct {
+	// Status is the launch status reported by am (for example "ok").
+	Status string
+	// LaunchState is "COLD", "WARM", "HOT", or "UNKNOWN (...)" on Android 10+.
+	LaunchState string
+	// Activity is the component that was launched.
+	Activity string
+	// TotalTime is the time from launch to the activity's first frame.
+	TotalTime time.Duration
+	// WaitTime is the total time am waited, including any pause of the
+	// previous activity.
+	WaitTime time.Duration
+}
+
+// StartIntent starts an activity, equivalent to `adb shell am start`. Failures
+// reported by am (for example an unresolvable intent) surface as
+// [ErrCommandFailed].
+func (d Device) StartIntent(ctx context.Context, intent Intent, opts StartOptions) (StartResult, error) {
+	var options []string
+	if opts.Wait {
+		options = append(options, "-W")
+	}
+	if opts.ForceStop {
+		options = append(options, "-S")
+	}
+	res, err := d.runChecked(ctx, intent.amArgs("start", options...)...)
+	if err != nil {
+		return StartResult{}, err
+	}
+	return parseStartResult(res.StdoutString()), nil
+}
+
+// SendBroadcast sends a broadcast intent, equivalent to
+// `adb shell am broadcast`.
+func (d Device) SendBroadcast(ctx context.Context, intent Intent) error {
+	return d.execChecked(ctx, intent.amArgs("broadcast")...)
+}
+
+// StartService starts a service, equivalent to `adb shell am startservice`.
+func (d Device) StartService(ctx context.Context, intent Intent) error {
+	return d.execChecked(ctx, intent.amArgs("startservice")...)
+}
+
+// StartForegroundService starts a foreground service, equivalent to
+// `adb shell am start-foreground-service` (Android 8+).
+func (d Device) StartForegroundService(ctx context.Context, intent Intent) error {
+	return d.execChecked(ctx, intent.amArgs("start-foreground-service")...)
+}
+
+// parseStartResult parses the "Key: value" report printed by `am start -W`.
+func parseStartResult(out string) StartResult {
+	var r StartResult
+	for line := range strings.SplitSeq(out, "\n") {
+		key, value, ok := strings.Cut(strings.TrimSpace(line), ": ")
+		if !ok {
+			continue
+		}
+		switch key {
+		case "Status":
+			r.Status = value
+		case "LaunchState":
+			r.LaunchState = value
+		case "Activity":
+			r.Activity = value
+		case "TotalTime":
+			r.TotalTime = parseMillis(value)
+		case "WaitTime":
+			r.WaitTime = parseMillis(value)
+		}
+	}
+	return r
+}
+
+func parseMillis(s string) time.Duration {
+	ms, _ := strconv.ParseInt(strings.TrimSpace(s), 10, 64)
+	return time.Duration(ms) * time.Millisecond
+}
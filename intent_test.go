@@ -0,0 +1,107 @@
+package adb
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestShellQuote(t *testing.T) {
+	tests := map[string]string{
+		"com.example/.Main": "com.example/.Main",
+		"":                  "''",
+		"hello world":       "'hello world'",
+		"it's":              `'it'\''s'`,
+		"a&b;c":             "'a&b;c'",
+		"$HOME":             "'$HOME'",
+	}
+	for in, want := range tests {
+		if got := shellQuote(in); got != want {
+			t.Fatalf("shellQuote(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestIntentArgs(t *testing.T) {
+	base := Intent{
+		Action:     "android.intent.action.VIEW",
+		Data:       "https://example.com/?a=1&b=2",
+		Categories: []string{"android.intent.category.BROWSABLE"},
+		Component:  "com.example/.Main",
+		Flags:      FlagActivityNewTask | FlagActivityClearTop,
+	}
+	intent := base.
+		WithString("msg", "hello world").
+		WithInt("count", 3).
+		WithBool("debug", true).
+		WithLong("id", 1<<40).
+		WithFloat("ratio", 0.5).
+		WithURI("link", "content://x").
+		WithStringArray("tags", "a,b", "c")
+	want := []string{
+		"-a", "android.intent.action.VIEW",
+		"-d", "'https://example.com/?a=1&b=2'",
+		"-c", "android.intent.category.BROWSABLE",
+		"-n", "com.example/.Main",
+		"-f", "0x14000000",
+		"--es", "msg", "'hello world'",
+		"--ei", "count", "3",
+		"--ez", "debug", "true",
+		"--el", "id", "1099511627776",
+		"--ef", "ratio", "0.5",
+		"--eu", "link", "content://x",
+		"--esa", "tags", `'a\,b,c'`,
+	}
+	if got := intent.args(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("args() = %q, want %q", got, want)
+	}
+	// With* must not mutate the receiver.
+	if len(base.extras) != 0 {
+		t.Fatalf("base intent mutated: %v", base.extras)
+	}
+}
+
+func TestStartIntent(t *testing.T) {
+	out := "Starting: Intent { cmp=com.example/.Main }\nStatus: ok\nLaunchState: COLD\nActivity: com.example/.Main\nTotalTime: 412\nWaitTime: 420\nComplete\n"
+	c, argsFile := fakeADB(t, out, "", 0)
+	got, err := c.Device("S").StartIntent(context.Background(),
+		Intent{Component: "com.example/.Main", User: "0"},
+		StartOptions{Wait: true, ForceStop: true},
+	)
+	if err != nil {
+		t.Fatalf("StartIntent() error = %v", err)
+	}
+	want := StartResult{Status: "ok", LaunchState: "COLD", Activity: "com.example/.Main", TotalTime: 412 * time.Millisecond, WaitTime: 420 * time.Millisecond}
+	if got != want {
+		t.Fatalf("StartIntent() = %#v, want %#v", got, want)
+	}
+	wantArgs := []string{"-s", "S", "shell", "am", "start", "-W", "-S", "--user", "0", "-n", "com.example/.Main"}
+	if args := readArgs(t, argsFile); !reflect.DeepEqual(args, wantArgs) {
+		t.Fatalf("StartIntent() args = %v, want %v", args, wantArgs)
+	}
+}
+
+func TestIntentCommands(t *testing.T) {
+	intent := Intent{Action: "com.example.PING", Package: "com.example"}
+	tests := []struct {
+		name string
+		call func(Device) error
+		want []string
+	}{
+		{"broadcast", func(d Device) error { return d.SendBroadcast(context.Background(), intent) }, []string{"-s", "S", "shell", "am", "broadcast", "-a", "com.example.PING", "-p", "com.example"}},
+		{"startservice", func(d Device) error { return d.StartService(context.Background(), intent) }, []string{"-s", "S", "shell", "am", "startservice", "-a", "com.example.PING", "-p", "com.example"}},
+		{"foreground service", func(d Device) error { return d.StartForegroundService(context.Background(), intent) }, []string{"-s", "S", "shell", "am", "start-foreground-service", "-a", "com.example.PING", "-p", "com.example"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, argsFile := fakeADB(t, "", "", 0)
+			if err := tt.call(c.Device("S")); err != nil {
+				t.Fatalf("%s error = %v", tt.name, err)
+			}
+			if got := readArgs(t, argsFile); !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("args = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
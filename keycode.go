@@ -0,0 +1,762 @@
+package adb
+
+import (
+	"context"
+	"slices"
+	"strconv"
+)
+
+// Keycode is an Android key code, from the KEYCODE_* constants of
+// android.view.KeyEvent. Values without a constant (for example vendor keys)
+// can be converted from their number.
+type Keycode int
+
+// Android key codes. The names follow android.view.KeyEvent, so
+// KEYCODE_MEDIA_PLAY_PAUSE is [KeycodeMediaPlayPause].
+const (
+	KeycodeUnknown                   Keycode = 0
+	KeycodeSoftLeft                  Keycode = 1
+	KeycodeSoftRight                 Keycode = 2
+	KeycodeHome                      Keycode = 3
+	KeycodeBack                      Keycode = 4
+	KeycodeCall                      Keycode = 5
+	KeycodeEndcall                   Keycode = 6
+	Keycode0                         Keycode = 7
+	Keycode1                         Keycode = 8
+	Keycode2                         Keycode = 9
+	Keycode3                         Keycode = 10
+	Keycode4                         Keycode = 11
+	Keycode5                         Keycode = 12
+	Keycode6                         Keycode = 13
+	Keycode7                         Keycode = 14
+	Keycode8                         Keycode = 15
+	Keycode9                         Keycode = 16
+	KeycodeStar                      Keycode = 17
+	KeycodePound                     Keycode = 18
+	KeycodeDPadUp                    Keycode = 19
+	KeycodeDPadDown                  Keycode = 20
+	KeycodeDPadLeft                  Keycode = 21
+	KeycodeDPadRight                 Keycode = 22
+	KeycodeDPadCenter                Keycode = 23
+	KeycodeVolumeUp                  Keycode = 24
+	KeycodeVolumeDown                Keycode = 25
+	KeycodePower                     Keycode = 26
+	KeycodeCamera                    Keycode = 27
+	KeycodeClear                     Keycode = 28
+	KeycodeA                         Keycode = 29
+	KeycodeB                         Keycode = 30
+	KeycodeC                         Keycode = 31
+	KeycodeD                         Keycode = 32
+	KeycodeE                         Keycode = 33
+	KeycodeF                         Keycode = 34
+	KeycodeG                         Keycode = 35
+	KeycodeH                         Keycode = 36
+	KeycodeI                         Keycode = 37
+	KeycodeJ                         Keycode = 38
+	KeycodeK                         Keycode = 39
+	KeycodeL                         Keycode = 40
+	KeycodeM                         Keycode = 41
+	KeycodeN                         Keycode = 42
+	KeycodeO                         Keycode = 43
+	KeycodeP                         Keycode = 44
+	KeycodeQ                         Keycode = 45
+	KeycodeR                         Keycode = 46
+	KeycodeS                         Keycode = 47
+	KeycodeT                         Keycode = 48
+	KeycodeU                         Keycode = 49
+	KeycodeV                         Keycode = 50
+	KeycodeW                         Keycode = 51
+	KeycodeX                         Keycode = 52
+	KeycodeY                         Keycode = 53
+	KeycodeZ                         Keycode = 54
+	KeycodeComma                     Keycode = 55
+	KeycodePeriod                    Keycode = 56
+	KeycodeAltLeft                   Keycode = 57
+	KeycodeAltRight                  Keycode = 58
+	KeycodeShiftLeft                 Keycode = 59
+	KeycodeShiftRight                Keycode = 60
+	KeycodeTab                       Keycode = 61
+	KeycodeSpace                     Keycode = 62
+	KeycodeSym                       Keycode = 63
+	KeycodeExplorer                  Keycode = 64
+	KeycodeEnvelope                  Keycode = 65
+	KeycodeEnter                     Keycode = 66
+	KeycodeDel                       Keycode = 67
+	KeycodeGrave                     Keycode = 68
+	KeycodeMinus                     Keycode = 69
+	KeycodeEquals                    Keycode = 70
+	KeycodeLeftBracket               Keycode = 71
+	KeycodeRightBracket              Keycode = 72
+	KeycodeBackslash                 Keycode = 73
+	KeycodeSemicolon                 Keycode = 74
+	KeycodeApostrophe                Keycode = 75
+	KeycodeSlash                     Keycode = 76
+	KeycodeAt                        Keycode = 77
+	KeycodeNum                       Keycode = 78
+	KeycodeHeadsethook               Keycode = 79
+	KeycodeFocus                     Keycode = 80
+	KeycodePlus                      Keycode = 81
+	KeycodeMenu                      Keycode = 82
+	KeycodeNotification              Keycode = 83
+	KeycodeSearch                    Keycode = 84
+	KeycodeMediaPlayPause            Keycode = 85
+	KeycodeMediaStop                 Keycode = 86
+	KeycodeMediaNext                 Keycode = 87
+	KeycodeMediaPrevious             Keycode = 88
+	KeycodeMediaRewind               Keycode = 89
+	KeycodeMediaFastForward          Keycode = 90
+	KeycodeMute                      Keycode = 91
+	KeycodePageUp                    Keycode = 92
+	KeycodePageDown                  Keycode = 93
+	KeycodePictsymbols               Keycode = 94
+	KeycodeSwitchCharset             Keycode = 95
+	KeycodeButtonA                   Keycode = 96
+	KeycodeButtonB                   Keycode = 97
+	KeycodeButtonC                   Keycode = 98
+	KeycodeButtonX                   Keycode = 99
+	KeycodeButtonY                   Keycode = 100
+	KeycodeButtonZ                   Keycode = 101
+	KeycodeButtonL1                  Keycode = 102
+	KeycodeButtonR1                  Keycode = 103
+	KeycodeButtonL2                  Keycode = 104
+	KeycodeButtonR2                  Keycode = 105
+	KeycodeButtonThumbl              Keycode = 106
+	KeycodeButtonThumbr              Keycode = 107
+	KeycodeButtonStart               Keycode = 108
+	KeycodeButtonSelect              Keycode = 109
+	KeycodeButtonMode                Keycode = 110
+	KeycodeEscape                    Keycode = 111
+	KeycodeForwardDel                Keycode = 112
+	KeycodeCtrlLeft                  Keycode = 113
+	KeycodeCtrlRight                 Keycode = 114
+	KeycodeCapsLock                  Keycode = 115
+	KeycodeScrollLock                Keycode = 116
+	KeycodeMetaLeft                  Keycode = 117
+	KeycodeMetaRight                 Keycode = 118
+	KeycodeFunction                  Keycode = 119
+	KeycodeSysrq                     Keycode = 120
+	KeycodeBreak                     Keycode = 121
+	KeycodeMoveHome                  Keycode = 122
+	KeycodeMoveEnd                   Keycode = 123
+	KeycodeInsert                    Keycode = 124
+	KeycodeForward                   Keycode = 125
+	KeycodeMediaPlay                 Keycode = 126
+	KeycodeMediaPause                Keycode = 127
+	KeycodeMediaClose                Keycode = 128
+	KeycodeMediaEject                Keycode = 129
+	KeycodeMediaRecord               Keycode = 130
+	KeycodeF1                        Keycode = 131
+	KeycodeF2                        Keycode = 132
+	KeycodeF3                        Keycode = 133
+	KeycodeF4                        Keycode = 134
+	KeycodeF5                        Keycode = 135
+	KeycodeF6                        Keycode = 136
+	KeycodeF7                        Keycode = 137
+	KeycodeF8                        Keycode = 138
+	KeycodeF9                        Keycode = 139
+	KeycodeF10                       Keycode = 140
+	KeycodeF11                       Keycode = 141
+	KeycodeF12                       Keycode = 142
+	KeycodeNumLock                   Keycode = 143
+	KeycodeNumpad0                   Keycode = 144
+	KeycodeNumpad1                   Keycode = 145
+	KeycodeNumpad2                   Keycode = 146
+	KeycodeNumpad3                   Keycode = 147
+	KeycodeNumpad4                   Keycode = 148
+	KeycodeNumpad5                   Keycode = 149
+	KeycodeNumpad6                   Keycode = 150
+	KeycodeNumpad7                   Keycode = 151
+	KeycodeNumpad8                   Keycode = 152
+	KeycodeNumpad9                   Keycode = 153
+	KeycodeNumpadDivide              Keycode = 154
+	KeycodeNumpadMultiply            Keycode = 155
+	KeycodeNumpadSubtract            Keycode = 156
+	KeycodeNumpadAdd                 Keycode = 157
+	KeycodeNumpadDot                 Keycode = 158
+	KeycodeNumpadComma               Keycode = 159
+	KeycodeNumpadEnter               Keycode = 160
+	KeycodeNumpadEquals              Keycode = 161
+	KeycodeNumpadLeftParen           Keycode = 162
+	KeycodeNumpadRightParen          Keycode = 163
+	KeycodeVolumeMute                Keycode = 164
+	KeycodeInfo                      Keycode = 165
+	KeycodeChannelUp                 Keycode = 166
+	KeycodeChannelDown               Keycode = 167
+	KeycodeZoomIn                    Keycode = 168
+	KeycodeZoomOut                   Keycode = 169
+	KeycodeTV                        Keycode = 170
+	KeycodeWindow                    Keycode = 171
+	KeycodeGuide                     Keycode = 172
+	KeycodeDVR                       Keycode = 173
+	KeycodeBookmark                  Keycode = 174
+	KeycodeCaptions                  Keycode = 175
+	KeycodeSettings                  Keycode = 176
+	KeycodeTVPower                   Keycode = 177
+	KeycodeTVInput                   Keycode = 178
+	KeycodeSTBPower                  Keycode = 179
+	KeycodeSTBInput                  Keycode = 180
+	KeycodeAVRPower                  Keycode = 181
+	KeycodeAVRInput                  Keycode = 182
+	KeycodeProgRed                   Keycode = 183
+	KeycodeProgGreen                 Keycode = 184
+	KeycodeProgYellow                Keycode = 185
+	KeycodeProgBlue                  Keycode = 186
+	KeycodeAppSwitch                 Keycode = 187
+	KeycodeButton1                   Keycode = 188
+	KeycodeButton2                   Keycode = 189
+	KeycodeButton3                   Keycode = 190
+	KeycodeButton4                   Keycode = 191
+	KeycodeButton5                   Keycode = 192
+	KeycodeButton6                   Keycode = 193
+	KeycodeButton7                   Keycode = 194
+	KeycodeButton8                   Keycode = 195
+	KeycodeButton9                   Keycode = 196
+	KeycodeButton10                  Keycode = 197
+	KeycodeButton11                  Keycode = 198
+	KeycodeButton12                  Keycode = 199
+	KeycodeButton13                  Keycode = 200
+	KeycodeButton14                  Keycode = 201
+	KeycodeButton15                  Keycode = 202
+	KeycodeButton16                  Keycode = 203
+	KeycodeLanguageSwitch            Keycode = 204
+	KeycodeMannerMode                Keycode = 205
+	Keycode3DMode                    Keycode = 206
+	KeycodeContacts                  Keycode = 207
+	KeycodeCalendar                  Keycode = 208
+	KeycodeMusic                     Keycode = 209
+	KeycodeCalculator                Keycode = 210
+	KeycodeZenkakuHankaku            Keycode = 211
+	KeycodeEisu                      Keycode = 212
+	KeycodeMuhenkan                  Keycode = 213
+	KeycodeHenkan                    Keycode = 214
+	KeycodeKatakanaHiragana          Keycode = 215
+	KeycodeYen                       Keycode = 216
+	KeycodeRo                        Keycode = 217
+	KeycodeKana                      Keycode = 218
+	KeycodeAssist                    Keycode = 219
+	KeycodeBrightnessDown            Keycode = 220
+	KeycodeBrightnessUp              Keycode = 221
+	KeycodeMediaAudioTrack           Keycode = 222
+	KeycodeSleep                     Keycode = 223
+	KeycodeWakeup                    Keycode = 224
+	KeycodePairing                   Keycode = 225
+	KeycodeMediaTopMenu              Keycode = 226
+	Keycode11                        Keycode = 227
+	Keycode12                        Keycode = 228
+	KeycodeLastChannel               Keycode = 229
+	KeycodeTVDataService             Keycode = 230
+	KeycodeVoiceAssist               Keycode = 231
+	KeycodeTVRadioService            Keycode = 232
+	KeycodeTVTeletext                Keycode = 233
+	KeycodeTVNumberEntry             Keycode = 234
+	KeycodeTVTerrestrialAnalog       Keycode = 235
+	KeycodeTVTerrestrialDigital      Keycode = 236
+	KeycodeTVSatellite               Keycode = 237
+	KeycodeTVSatelliteBS             Keycode = 238
+	KeycodeTVSatelliteCS             Keycode = 239
+	KeycodeTVSatelliteService        Keycode = 240
+	KeycodeTVNetwork                 Keycode = 241
+	KeycodeTVAntennaCable            Keycode = 242
+	KeycodeTVInputHDMI1              Keycode = 243
+	KeycodeTVInputHDMI2              Keycode = 244
+	KeycodeTVInputHDMI3              Keycode = 245
+	KeycodeTVInputHDMI4              Keycode = 246
+	KeycodeTVInputComposite1         Keycode = 247
+	KeycodeTVInputComposite2         Keycode = 248
+	KeycodeTVInputComponent1         Keycode = 249
+	KeycodeTVInputComponent2         Keycode = 250
+	KeycodeTVInputVGA1               Keycode = 251
+	KeycodeTVAudioDescription        Keycode = 252
+	KeycodeTVAudioDescriptionMixUp   Keycode = 253
+	KeycodeTVAudioDescriptionMixDown Keycode = 254
+	KeycodeTVZoomMode                Keycode = 255
+	KeycodeTVContentsMenu            Keycode = 256
+	KeycodeTVMediaContextMenu        Keycode = 257
+	KeycodeTVTimerProgramming        Keycode = 258
+	KeycodeHelp                      Keycode = 259
+	KeycodeNavigatePrevious          Keycode = 260
+	KeycodeNavigateNext              Keycode = 261
+	KeycodeNavigateIn                Keycode = 262
+	KeycodeNavigateOut               Keycode = 263
+	KeycodeStemPrimary               Keycode = 264
+	KeycodeStem1                     Keycode = 265
+	KeycodeStem2                     Keycode = 266
+	KeycodeStem3                     Keycode = 267
+	KeycodeDPadUpLeft                Keycode = 268
+	KeycodeDPadDownLeft              Keycode = 269
+	KeycodeDPadUpRight               Keycode = 270
+	KeycodeDPadDownRight             Keycode = 271
+	KeycodeMediaSkipForward          Keycode = 272
+	KeycodeMediaSkipBackward         Keycode = 273
+	KeycodeMediaStepForward          Keycode = 274
+	KeycodeMediaStepBackward         Keycode = 275
+	KeycodeSoftSleep                 Keycode = 276
+	KeycodeCut                       Keycode = 277
+	KeycodeCopy                      Keycode = 278
+	KeycodePaste                     Keycode = 279
+	KeycodeSystemNavigationUp        Keycode = 280
+	KeycodeSystemNavigationDown      Keycode = 281
+	KeycodeSystemNavigationLeft      Keycode = 282
+	KeycodeSystemNavigationRight     Keycode = 283
+	KeycodeAllApps                   Keycode = 284
+	KeycodeRefresh                   Keycode = 285
+	KeycodeThumbsUp                  Keycode = 286
+	KeycodeThumbsDown                Keycode = 287
+	KeycodeProfileSwitch             Keycode = 288
+	KeycodeVideoApp1                 Keycode = 289
+	KeycodeVideoApp2                 Keycode = 290
+	KeycodeVideoApp3                 Keycode = 291
+	KeycodeVideoApp4                 Keycode = 292
+	KeycodeVideoApp5                 Keycode = 293
+	KeycodeVideoApp6                 Keycode = 294
+	KeycodeVideoApp7                 Keycode = 295
+	KeycodeVideoApp8                 Keycode = 296
+	KeycodeFeaturedApp1              Keycode = 297
+	KeycodeFeaturedApp2              Keycode = 298
+	KeycodeFeaturedApp3              Keycode = 299
+	KeycodeFeaturedApp4              Keycode = 300
+	KeycodeDemoApp1                  Keycode = 301
+	KeycodeDemoApp2                  Keycode = 302
+	KeycodeDemoApp3                  Keycode = 303
+	KeycodeDemoApp4                  Keycode = 304
+	KeycodeKeyboardBacklightDown     Keycode = 305
+	KeycodeKeyboardBacklightUp       Keycode = 306
+	KeycodeKeyboardBacklightToggle   Keycode = 307
+	KeycodeStylusButtonPrimary       Keycode = 308
+	KeycodeStylusButtonSecondary     Keycode = 309
+	KeycodeStylusButtonTertiary      Keycode = 310
+	KeycodeStylusButtonTail          Keycode = 311
+	KeycodeRecentApps                Keycode = 312
+	KeycodeMacro1                    Keycode = 313
+	KeycodeMacro2                    Keycode = 314
+	KeycodeMacro3                    Keycode = 315
+	KeycodeMacro4                    Keycode = 316
+	KeycodeEmojiPicker               Keycode = 317
+	KeycodeScreenshot                Keycode = 318
+)
+
+var keycodeNames = map[Keycode]string{
+	KeycodeUnknown:                   "KEYCODE_UNKNOWN",
+	KeycodeSoftLeft:                  "KEYCODE_SOFT_LEFT",
+	KeycodeSoftRight:                 "KEYCODE_SOFT_RIGHT",
+	KeycodeHome:                      "KEYCODE_HOME",
+	KeycodeBack:                      "KEYCODE_BACK",
+	KeycodeCall:                      "KEYCODE_CALL",
+	KeycodeEndcall:                   "KEYCODE_ENDCALL",
+	Keycode0:                         "KEYCODE_0",
+	Keycode1:                         "KEYCODE_1",
+	Keycode2:                         "KEYCODE_2",
+	Keycode3:                         "KEYCODE_3",
+	Keycode4:                         "KEYCODE_4",
+	Keycode5:                         "KEYCODE_5",
+	Keycode6:                         "KEYCODE_6",
+	Keycode7:                         "KEYCODE_7",
+	Keycode8:                         "KEYCODE_8",
+	Keycode9:                         "KEYCODE_9",
+	KeycodeStar:                      "KEYCODE_STAR",
+	KeycodePound:                     "KEYCODE_POUND",
+	KeycodeDPadUp:                    "KEYCODE_DPAD_UP",
+	KeycodeDPadDown:                  "KEYCODE_DPAD_DOWN",
+	KeycodeDPadLeft:                  "KEYCODE_DPAD_LEFT",
+	KeycodeDPadRight:                 "KEYCODE_DPAD_RIGHT",
+	KeycodeDPadCenter:                "KEYCODE_DPAD_CENTER",
+	KeycodeVolumeUp:                  "KEYCODE_VOLUME_UP",
+	KeycodeVolumeDown:                "KEYCODE_VOLUME_DOWN",
+	KeycodePower:                     "KEYCODE_POWER",
+	KeycodeCamera:                    "KEYCODE_CAMERA",
+	KeycodeClear:                     "KEYCODE_CLEAR",
+	KeycodeA:                         "KEYCODE_A",
+	KeycodeB:                         "KEYCODE_B",
+	KeycodeC:                         "KEYCODE_C",
+	KeycodeD:                         "KEYCODE_D",
+	KeycodeE:                         "KEYCODE_E",
+	KeycodeF:                         "KEYCODE_F",
+	KeycodeG:                         "KEYCODE_G",
+	KeycodeH:                         "KEYCODE_H",
+	KeycodeI:                         "KEYCODE_I",
+	KeycodeJ:                         "KEYCODE_J",
+	KeycodeK:                         "KEYCODE_K",
+	KeycodeL:                         "KEYCODE_L",
+	KeycodeM:                         "KEYCODE_M",
+	KeycodeN:                         "KEYCODE_N",
+	KeycodeO:                         "KEYCODE_O",
+	KeycodeP:                         "KEYCODE_P",
+	KeycodeQ:                         "KEYCODE_Q",
+	KeycodeR:                         "KEYCODE_R",
+	KeycodeS:                         "KEYCODE_S",
+	KeycodeT:                         "KEYCODE_T",
+	KeycodeU:                         "KEYCODE_U",
+	KeycodeV:                         "KEYCODE_V",
+	KeycodeW:                         "KEYCODE_W",
+	KeycodeX:                         "KEYCODE_X",
+	KeycodeY:                         "KEYCODE_Y",
+	KeycodeZ:                         "KEYCODE_Z",
+	KeycodeComma:                     "KEYCODE_COMMA",
+	KeycodePeriod:                    "KEYCODE_PERIOD",
+	KeycodeAltLeft:                   "KEYCODE_ALT_LEFT",
+	KeycodeAltRight:                  "KEYCODE_ALT_RIGHT",
+	KeycodeShiftLeft:                 "KEYCODE_SHIFT_LEFT",
+	KeycodeShiftRight:                "KEYCODE_SHIFT_RIGHT",
+	KeycodeTab:                       "KEYCODE_TAB",
+	KeycodeSpace:                     "KEYCODE_SPACE",
+	KeycodeSym:                       "KEYCODE_SYM",
+	KeycodeExplorer:                  "KEYCODE_EXPLORER",
+	KeycodeEnvelope:                  "KEYCODE_ENVELOPE",
+	KeycodeEnter:                     "KEYCODE_ENTER",
+	KeycodeDel:                       "KEYCODE_DEL",
+	KeycodeGrave:                     "KEYCODE_GRAVE",
+	KeycodeMinus:                     "KEYCODE_MINUS",
+	KeycodeEquals:                    "KEYCODE_EQUALS",
+	KeycodeLeftBracket:               "KEYCODE_LEFT_BRACKET",
+	KeycodeRightBracket:              "KEYCODE_RIGHT_BRACKET",
+	KeycodeBackslash:                 "KEYCODE_BACKSLASH",
+	KeycodeSemicolon:                 "KEYCODE_SEMICOLON",
+	KeycodeApostrophe:                "KEYCODE_APOSTROPHE",
+	KeycodeSlash:                     "KEYCODE_SLASH",
+	KeycodeAt:                        "KEYCODE_AT",
+	KeycodeNum:                       "KEYCODE_NUM",
+	KeycodeHeadsethook:               "KEYCODE_HEADSETHOOK",
+	KeycodeFocus:                     "KEYCODE_FOCUS",
+	KeycodePlus:                      "KEYCODE_PLUS",
+	KeycodeMenu:                      "KEYCODE_MENU",
+	KeycodeNotification:              "KEYCODE_NOTIFICATION",
+	KeycodeSearch:                    "KEYCODE_SEARCH",
+	KeycodeMediaPlayPause:            "KEYCODE_MEDIA_PLAY_PAUSE",
+	KeycodeMediaStop:                 "KEYCODE_MEDIA_STOP",
+	KeycodeMediaNext:                 "KEYCODE_MEDIA_NEXT",
+	KeycodeMediaPrevious:             "KEYCODE_MEDIA_PREVIOUS",
+	KeycodeMediaRewind:               "KEYCODE_MEDIA_REWIND",
+	KeycodeMediaFastForward:          "KEYCODE_MEDIA_FAST_FORWARD",
+	KeycodeMute:                      "KEYCODE_MUTE",
+	KeycodePageUp:                    "KEYCODE_PAGE_UP",
+	KeycodePageDown:                  "KEYCODE_PAGE_DOWN",
+	KeycodePictsymbols:               "KEYCODE_PICTSYMBOLS",
+	KeycodeSwitchCharset:             "KEYCODE_SWITCH_CHARSET",
+	KeycodeButtonA:                   "KEYCODE_BUTTON_A",
+	KeycodeButtonB:                   "KEYCODE_BUTTON_B",
+	KeycodeButtonC:                   "KEYCODE_BUTTON_C",
+	KeycodeButtonX:                   "KEYCODE_BUTTON_X",
+	KeycodeButtonY:                   "KEYCODE_BUTTON_Y",
+	KeycodeButtonZ:                   "KEYCODE_BUTTON_Z",
+	KeycodeButtonL1:                  "KEYCODE_BUTTON_L1",
+	KeycodeButtonR1:                  "KEYCODE_BUTTON_R1",
+	KeycodeButtonL2:                  "KEYCODE_BUTTON_L2",
+	KeycodeButtonR2:                  "KEYCODE_BUTTON_R2",
+	KeycodeButtonThumbl:              "KEYCODE_BUTTON_THUMBL",
+	KeycodeButtonThumbr:              "KEYCODE_BUTTON_THUMBR",
+	KeycodeButtonStart:               "KEYCODE_BUTTON_START",
+	KeycodeButtonSelect:              "KEYCODE_BUTTON_SELECT",
+	KeycodeButtonMode:                "KEYCODE_BUTTON_MODE",
+	KeycodeEscape:                    "KEYCODE_ESCAPE",
+	KeycodeForwardDel:                "KEYCODE_FORWARD_DEL",
+	KeycodeCtrlLeft:                  "KEYCODE_CTRL_LEFT",
+	KeycodeCtrlRight:                 "KEYCODE_CTRL_RIGHT",
+	KeycodeCapsLock:                  "KEYCODE_CAPS_LOCK",
+	KeycodeScrollLock:                "KEYCODE_SCROLL_LOCK",
+	KeycodeMetaLeft:                  "KEYCODE_META_LEFT",
+	KeycodeMetaRight:                 "KEYCODE_META_RIGHT",
+	KeycodeFunction:                  "KEYCODE_FUNCTION",
+	KeycodeSysrq:                     "KEYCODE_SYSRQ",
+	KeycodeBreak:                     "KEYCODE_BREAK",
+	KeycodeMoveHome:                  "KEYCODE_MOVE_HOME",
+	KeycodeMoveEnd:                   "KEYCODE_MOVE_END",
+	KeycodeInsert:                    "KEYCODE_INSERT",
+	KeycodeForward:                   "KEYCODE_FORWARD",
+	KeycodeMediaPlay:                 "KEYCODE_MEDIA_PLAY",
+	KeycodeMediaPause:                "KEYCODE_MEDIA_PAUSE",
+	KeycodeMediaClose:                "KEYCODE_MEDIA_CLOSE",
+	KeycodeMediaEject:                "KEYCODE_MEDIA_EJECT",
+	KeycodeMediaRecord:               "KEYCODE_MEDIA_RECORD",
+	KeycodeF1:                        "KEYCODE_F1",
+	KeycodeF2:                        "KEYCODE_F2",
+	KeycodeF3:                        "KEYCODE_F3",
+	KeycodeF4:                        "KEYCODE_F4",
+	KeycodeF5:                        "KEYCODE_F5",
+	KeycodeF6:                        "KEYCODE_F6",
+	KeycodeF7:                        "KEYCODE_F7",
+	KeycodeF8:                        "KEYCODE_F8",
+	KeycodeF9:                        "KEYCODE_F9",
+	KeycodeF10:                       "KEYCODE_F10",
+	KeycodeF11:                       "KEYCODE_F11",
+	KeycodeF12:                       "KEYCODE_F12",
+	KeycodeNumLock:                   "KEYCODE_NUM_LOCK",
+	KeycodeNumpad0:                   "KEYCODE_NUMPAD_0",
+	KeycodeNumpad1:                   "KEYCODE_NUMPAD_1",
+	KeycodeNumpad2:                   "KEYCODE_NUMPAD_2",
+	KeycodeNumpad3:                   "KEYCODE_NUMPAD_3",
+	KeycodeNumpad4:                   "KEYCODE_NUMPAD_4",
+	KeycodeNumpad5:                   "KEYCODE_NUMPAD_5",
+	KeycodeNumpad6:                   "KEYCODE_NUMPAD_6",
+	KeycodeNumpad7:                   "KEYCODE_NUMPAD_7",
+	KeycodeNumpad8:                   "KEYCODE_NUMPAD_8",
+	KeycodeNumpad9:                   "KEYCODE_NUMPAD_9",
+	KeycodeNumpadDivide:              "KEYCODE_NUMPAD_DIVIDE",
+	KeycodeNumpadMultiply:            "KEYCODE_NUMPAD_MULTIPLY",
+	KeycodeNumpadSubtract:            "KEYCODE_NUMPAD_SUBTRACT",
+	KeycodeNumpadAdd:                 "KEYCODE_NUMPAD_ADD",
+	KeycodeNumpadDot:                 "KEYCODE_NUMPAD_DOT",
+	KeycodeNumpadComma:               "KEYCODE_NUMPAD_COMMA",
+	KeycodeNumpadEnter:               "KEYCODE_NUMPAD_ENTER",
+	KeycodeNumpadEquals:              "KEYCODE_NUMPAD_EQUALS",
+	KeycodeNumpadLeftParen:           "KEYCODE_NUMPAD_LEFT_PAREN",
+	KeycodeNumpadRightParen:          "KEYCODE_NUMPAD_RIGHT_PAREN",
+	KeycodeVolumeMute:                "KEYCODE_VOLUME_MUTE",
+	KeycodeInfo:                      "KEYCODE_INFO",
+	KeycodeChannelUp:                 "KEYCODE_CHANNEL_UP",
+	KeycodeChannelDown:               "KEYCODE_CHANNEL_DOWN",
+	KeycodeZoomIn:                    "KEYCODE_ZOOM_IN",
+	KeycodeZoomOut:                   "KEYCODE_ZOOM_OUT",
+	KeycodeTV:                        "KEYCODE_TV",
+	KeycodeWindow:                    "KEYCODE_WINDOW",
+	KeycodeGuide:                     "KEYCODE_GUIDE",
+	KeycodeDVR:                       "KEYCODE_DVR",
+	KeycodeBookmark:                  "KEYCODE_BOOKMARK",
+	KeycodeCaptions:                  "KEYCODE_CAPTIONS",
+	KeycodeSettings:                  "KEYCODE_SETTINGS",
+	KeycodeTVPower:                   "KEYCODE_TV_POWER",
+	KeycodeTVInput:                   "KEYCODE_TV_INPUT",
+	KeycodeSTBPower:                  "KEYCODE_STB_POWER",
+	KeycodeSTBInput:                  "KEYCODE_STB_INPUT",
+	KeycodeAVRPower:                  "KEYCODE_AVR_POWER",
+	KeycodeAVRInput:                  "KEYCODE_AVR_INPUT",
+	KeycodeProgRed:                   "KEYCODE_PROG_RED",
+	KeycodeProgGreen:                 "KEYCODE_PROG_GREEN",
+	KeycodeProgYellow:                "KEYCODE_PROG_YELLOW",
+	KeycodeProgBlue:                  "KEYCODE_PROG_BLUE",
+	KeycodeAppSwitch:                 "KEYCODE_APP_SWITCH",
+	KeycodeButton1:                   "KEYCODE_BUTTON_1",
+	KeycodeButton2:                   "KEYCODE_BUTTON_2",
+	KeycodeButton3:                   "KEYCODE_BUTTON_3",
+	KeycodeButton4:                   "KEYCODE_BUTTON_4",
+	KeycodeButton5:                   "KEYCODE_BUTTON_5",
+	KeycodeButton6:                   "KEYCODE_BUTTON_6",
+	KeycodeButton7:                   "KEYCODE_BUTTON_7",
+	KeycodeButton8:                   "KEYCODE_BUTTON_8",
+	KeycodeButton9:                   "KEYCODE_BUTTON_9",
+	KeycodeButton10:                  "KEYCODE_BUTTON_10",
+	KeycodeButton11:                  "KEYCODE_BUTTON_11",
+	KeycodeButton12:                  "KEYCODE_BUTTON_12",
+	KeycodeButton13:                  "KEYCODE_BUTTON_13",
+	KeycodeButton14:                  "KEYCODE_BUTTON_14",
+	KeycodeButton15:                  "KEYCODE_BUTTON_15",
+	KeycodeButton16:                  "KEYCODE_BUTTON_16",
+	KeycodeLanguageSwitch:            "KEYCODE_LANGUAGE_SWITCH",
+	KeycodeMannerMode:                "KEYCODE_MANNER_MODE",
+	Keycode3DMode:                    "KEYCODE_3D_MODE",
+	KeycodeContacts:                  "KEYCODE_CONTACTS",
+	KeycodeCalendar:                  "KEYCODE_CALENDAR",
+	KeycodeMusic:                     "KEYCODE_MUSIC",
+	KeycodeCalculator:                "KEYCODE_CALCULATOR",
+	KeycodeZenkakuHankaku:            "KEYCODE_ZENKAKU_HANKAKU",
+	KeycodeEisu:                      "KEYCODE_EISU",
+	KeycodeMuhenkan:                  "KEYCODE_MUHENKAN",
+	KeycodeHenkan:                    "KEYCODE_HENKAN",
+	KeycodeKatakanaHiragana:          "KEYCODE_KATAKANA_HIRAGANA",
+	KeycodeYen:                       "KEYCODE_YEN",
+	KeycodeRo:                        "KEYCODE_RO",
+	KeycodeKana:                      "KEYCODE_KANA",
+	KeycodeAssist:                    "KEYCODE_ASSIST",
+	KeycodeBrightnessDown:            "KEYCODE_BRIGHTNESS_DOWN",
+	KeycodeBrightnessUp:              "KEYCODE_BRIGHTNESS_UP",
+	KeycodeMediaAudioTrack:           "KEYCODE_MEDIA_AUDIO_TRACK",
+	KeycodeSleep:                     "KEYCODE_SLEEP",
+	KeycodeWakeup:                    "KEYCODE_WAKEUP",
+	KeycodePairing:                   "KEYCODE_PAIRING",
+	KeycodeMediaTopMenu:              "KEYCODE_MEDIA_TOP_MENU",
+	Keycode11:                        "KEYCODE_11",
+	Keycode12:                        "KEYCODE_12",
+	KeycodeLastChannel:               "KEYCODE_LAST_CHANNEL",
+	KeycodeTVDataService:             "KEYCODE_TV_DATA_SERVICE",
+	KeycodeVoiceAssist:               "KEYCODE_VOICE_ASSIST",
+	KeycodeTVRadioService:            "KEYCODE_TV_RADIO_SERVICE",
+	KeycodeTVTeletext:                "KEYCODE_TV_TELETEXT",
+	KeycodeTVNumberEntry:             "KEYCODE_TV_NUMBER_ENTRY",
+	KeycodeTVTerrestrialAnalog:       "KEYCODE_TV_TERRESTRIAL_ANALOG",
+	KeycodeTVTerrestrialDigital:      "KEYCODE_TV_TERRESTRIAL_DIGITAL",
+	KeycodeTVSatellite:               "KEYCODE_TV_SATELLITE",
+	KeycodeTVSatelliteBS:             "KEYCODE_TV_SATELLITE_BS",
+	KeycodeTVSatelliteCS:             "KEYCODE_TV_SATELLITE_CS",
+	KeycodeTVSatelliteService:        "KEYCODE_TV_SATELLITE_SERVICE",
+	KeycodeTVNetwork:                 "KEYCODE_TV_NETWORK",
+	KeycodeTVAntennaCable:            "KEYCODE_TV_ANTENNA_CABLE",
+	KeycodeTVInputHDMI1:              "KEYCODE_TV_INPUT_HDMI_1",
+	KeycodeTVInputHDMI2:              "KEYCODE_TV_INPUT_HDMI_2",
+	KeycodeTVInputHDMI3:              "KEYCODE_TV_INPUT_HDMI_3",
+	KeycodeTVInputHDMI4:              "KEYCODE_TV_INPUT_HDMI_4",
+	KeycodeTVInputComposite1:         "KEYCODE_TV_INPUT_COMPOSITE_1",
+	KeycodeTVInputComposite2:         "KEYCODE_TV_INPUT_COMPOSITE_2",
+	KeycodeTVInputComponent1:         "KEYCODE_TV_INPUT_COMPONENT_1",
+	KeycodeTVInputComponent2:         "KEYCODE_TV_INPUT_COMPONENT_2",
+	KeycodeTVInputVGA1:               "KEYCODE_TV_INPUT_VGA_1",
+	KeycodeTVAudioDescription:        "KEYCODE_TV_AUDIO_DESCRIPTION",
+	KeycodeTVAudioDescriptionMixUp:   "KEYCODE_TV_AUDIO_DESCRIPTION_MIX_UP",
+	KeycodeTVAudioDescriptionMixDown: "KEYCODE_TV_AUDIO_DESCRIPTION_MIX_DOWN",
+	KeycodeTVZoomMode:                "KEYCODE_TV_ZOOM_MODE",
+	KeycodeTVContentsMenu:            "KEYCODE_TV_CONTENTS_MENU",
+	KeycodeTVMediaContextMenu:        "KEYCODE_TV_MEDIA_CONTEXT_MENU",
+	KeycodeTVTimerProgramming:        "KEYCODE_TV_TIMER_PROGRAMMING",
+	KeycodeHelp:                      "KEYCODE_HELP",
+	KeycodeNavigatePrevious:          "KEYCODE_NAVIGATE_PREVIOUS",
+	KeycodeNavigateNext:              "KEYCODE_NAVIGATE_NEXT",
+	KeycodeNavigateIn:                "KEYCODE_NAVIGATE_IN",
+	KeycodeNavigateOut:               "KEYCODE_NAVIGATE_OUT",
+	KeycodeStemPrimary:               "KEYCODE_STEM_PRIMARY",
+	KeycodeStem1:                     "KEYCODE_STEM_1",
+	KeycodeStem2:                     "KEYCODE_STEM_2",
+	KeycodeStem3:                     "KEYCODE_STEM_3",
+	KeycodeDPadUpLeft:                "KEYCODE_DPAD_UP_LEFT",
+	KeycodeDPadDownLeft:              "KEYCODE_DPAD_DOWN_LEFT",
+	KeycodeDPadUpRight:               "KEYCODE_DPAD_UP_RIGHT",
+	KeycodeDPadDownRight:             "KEYCODE_DPAD_DOWN_RIGHT",
+	KeycodeMediaSkipForward:          "KEYCODE_MEDIA_SKIP_FORWARD",
+	KeycodeMediaSkipBackward:         "KEYCODE_MEDIA_SKIP_BACKWARD",
+	KeycodeMediaStepForward:          "KEYCODE_MEDIA_STEP_FORWARD",
+	KeycodeMediaStepBackward:         "KEYCODE_MEDIA_STEP_BACKWARD",
+	KeycodeSoftSleep:                 "KEYCODE_SOFT_SLEEP",
+	KeycodeCut:                       "KEYCODE_CUT",
+	KeycodeCopy:                      "KEYCODE_COPY",
+	KeycodePaste:                     "KEYCODE_PASTE",
+	KeycodeSystemNavigationUp:        "KEYCODE_SYSTEM_NAVIGATION_UP",
+	KeycodeSystemNavigationDown:      "KEYCODE_SYSTEM_NAVIGATION_DOWN",
+	KeycodeSystemNavigationLeft:      "KEYCODE_SYSTEM_NAVIGATION_LEFT",
+	KeycodeSystemNavigationRight:     "KEYCODE_SYSTEM_NAVIGATION_RIGHT",
+	KeycodeAllApps:                   "KEYCODE_ALL_APPS",
+	KeycodeRefresh:                   "KEYCODE_REFRESH",
+	KeycodeThumbsUp:                  "KEYCODE_THUMBS_UP",
+	KeycodeThumbsDown:                "KEYCODE_THUMBS_DOWN",
+	KeycodeProfileSwitch:             "KEYCODE_PROFILE_SWITCH",
+	KeycodeVideoApp1:                 "KEYCODE_VIDEO_APP_1",
+	KeycodeVideoApp2:                 "KEYCODE_VIDEO_APP_2",
+	KeycodeVideoApp3:                 "KEYCODE_VIDEO_APP_3",
+	KeycodeVideoApp4:                 "KEYCODE_VIDEO_APP_4",
+	KeycodeVideoApp5:                 "KEYCODE_VIDEO_APP_5",
+	KeycodeVideoApp6:                 "KEYCODE_VIDEO_APP_6",
+	KeycodeVideoApp7:                 "KEYCODE_VIDEO_APP_7",
+	KeycodeVideoApp8:                 "KEYCODE_VIDEO_APP_8",
+	KeycodeFeaturedApp1:              "KEYCODE_FEATURED_APP_1",
+	KeycodeFeaturedApp2:              "KEYCODE_FEATURED_APP_2",
+	KeycodeFeaturedApp3:              "KEYCODE_FEATURED_APP_3",
+	KeycodeFeaturedApp4:              "KEYCODE_FEATURED_APP_4",
+	KeycodeDemoApp1:                  "KEYCODE_DEMO_APP_1",
+	KeycodeDemoApp2:                  "KEYCODE_DEMO_APP_2",
+	KeycodeDemoApp3:                  "KEYCODE_DEMO_APP_3",
+	KeycodeDemoApp4:                  "KEYCODE_DEMO_APP_4",
+	KeycodeKeyboardBacklightDown:     "KEYCODE_KEYBOARD_BACKLIGHT_DOWN",
+	KeycodeKeyboardBacklightUp:       "KEYCODE_KEYBOARD_BACKLIGHT_UP",
+	KeycodeKeyboardBacklightToggle:   "KEYCODE_KEYBOARD_BACKLIGHT_TOGGLE",
+	KeycodeStylusButtonPrimary:       "KEYCODE_STYLUS_BUTTON_PRIMARY",
+	KeycodeStylusButtonSecondary:     "KEYCODE_STYLUS_BUTTON_SECONDARY",
+	KeycodeStylusButtonTertiary:      "KEYCODE_STYLUS_BUTTON_TERTIARY",
+	KeycodeStylusButtonTail:          "KEYCODE_STYLUS_BUTTON_TAIL",
+	KeycodeRecentApps:                "KEYCODE_RECENT_APPS",
+	KeycodeMacro1:                    "KEYCODE_MACRO_1",
+	KeycodeMacro2:                    "KEYCODE_MACRO_2",
+	KeycodeMacro3:                    "KEYCODE_MACRO_3",
+	KeycodeMacro4:                    "KEYCODE_MACRO_4",
+	KeycodeEmojiPicker:               "KEYCODE_EMOJI_PICKER",
+	KeycodeScreenshot:                "KEYCODE_SCREENSHOT",
+}
+
+// String returns the key's KEYCODE_ name, for example "KEYCODE_HOME", or the
+// decimal code for keys without a constant. It is meant for display and
+// logging: input commands send the decimal code, which every release
+// understands, whereas `input` ignores names added after its release.
+func (k Keycode) String() string {
+	if name, ok := keycodeNames[k]; ok {
+		return name
+	}
+	return strconv.Itoa(int(k))
+}
+
+// MetaState is a set of modifier keys for [Device.KeyCombination], using the
+// META_*_ON flags of android.view.KeyEvent.
+type MetaState int
+
+const (
+	// MetaShift holds down shift.
+	MetaShift MetaState = 0x1
+	// MetaAlt holds down alt.
+	MetaAlt MetaState = 0x2
+	// MetaCtrl holds down ctrl.
+	MetaCtrl MetaState = 0x1000
+	// MetaMeta holds down the meta (Windows or Command) key.
+	MetaMeta MetaState = 0x10000
+)
+
+// keycodes returns the left-hand modifier keys for the meta state.
+func (m MetaState) keycodes() []Keycode {
+	var keys []Keycode
+	for _, mod := range []struct {
+		flag MetaState
+		key  Keycode
+	}{{MetaCtrl, KeycodeCtrlLeft}, {MetaAlt, KeycodeAltLeft}, {MetaShift, KeycodeShiftLeft}, {MetaMeta, KeycodeMetaLeft}} {
+		if m&mod.flag != 0 {
+			keys = append(keys, mod.key)
+		}
+	}
+	return keys
+}
+
+// keycodeArgs renders keys as decimal `input` arguments.
+func keycodeArgs(keys []Keycode) []string {
+	args := make([]string, len(keys))
+	for i, k := range keys {
+		args[i] = strconv.Itoa(int(k))
+	}
+	return args
+}
+
+// KeyEvent sends a key press, equivalent to `adb shell input keyevent <key>`.
+func (d Device) KeyEvent(ctx context.Context, key Keycode) error {
+	return d.KeyEvents(ctx, key)
+}
+
+// KeyEventNames sends key presses given as raw `input keyevent` arguments,
+// either KEYCODE_ names such as "KEYCODE_HOME" or decimal codes, in a single
+// call. It serves callers of the string-based KeyEvent that predates
+// [Keycode]; new code should use [Device.KeyEvents].
+func (d Device) KeyEventNames(ctx context.Context, keys ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	return d.input(ctx, "keyevent", keys...)
+}
+
+// KeyEvents sends several key presses in order with a single
+// `adb shell input keyevent <key>...` call, avoiding a round trip per key.
+func (d Device) KeyEvents(ctx context.Context, keys ...Keycode) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	return d.input(ctx, "keyevent", keycodeArgs(keys)...)
+}
+
+// RepeatKey presses key n times in a single `input keyevent` call, for
+// example to delete n characters with [KeycodeDel].
+func (d Device) RepeatKey(ctx context.Context, key Keycode, n int) error {
+	return d.KeyEvents(ctx, slices.Repeat([]Keycode{key}, max(n, 0))...)
+}
+
+// LongPressKey long-presses key, equivalent to
+// `adb shell input keyevent --longpress <key>`.
+func (d Device) LongPressKey(ctx context.Context, key Keycode) error {
+	return d.input(ctx, "keyevent", "--longpress", strconv.Itoa(int(key)))
+}
+
+// KeyCombination presses key while holding the modifiers in meta, for
+// example MetaCtrl and [KeycodeA] to select all. It is equivalent to
+// `adb shell input keycombination <modifiers>... <key>` and requires
+// Android 13 or later.
+func (d Device) KeyCombination(ctx context.Context, meta MetaState, key Keycode) error {
+	keys := append(meta.keycodes(), key)
+	return d.input(ctx, "keycombination", keycodeArgs(keys)...)
+}
+
+// GoHome presses the home button.
+func (d Device) GoHome(ctx context.Context) error { return d.KeyEvent(ctx, KeycodeHome) }
+
+// GoBack presses the back button.
+func (d Device) GoBack(ctx context.Context) error { return d.KeyEvent(ctx, KeycodeBack) }
+
+// SwitchApp opens the app switcher. You probably want to call this twice.
+func (d Device) SwitchApp(ctx context.Context) error { return d.KeyEvent(ctx, KeycodeAppSwitch) }
@@ -0,0 +1,86 @@
+package adb
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestKeycodeString(t *testing.T) {
+	tests := map[Keycode]string{
+		KeycodeHome:                    "KEYCODE_HOME",
+		Keycode0:                       "KEYCODE_0",
+		KeycodeZ:                       "KEYCODE_Z",
+		KeycodeF12:                     "KEYCODE_F12",
+		KeycodeDPadCenter:              "KEYCODE_DPAD_CENTER",
+		KeycodeMediaPlayPause:          "KEYCODE_MEDIA_PLAY_PAUSE",
+		Keycode3DMode:                  "KEYCODE_3D_MODE",
+		KeycodeProfileSwitch:           "KEYCODE_PROFILE_SWITCH",
+		KeycodeVideoApp1:               "KEYCODE_VIDEO_APP_1",
+		KeycodeFeaturedApp4:            "KEYCODE_FEATURED_APP_4",
+		KeycodeKeyboardBacklightToggle: "KEYCODE_KEYBOARD_BACKLIGHT_TOGGLE",
+		KeycodeStylusButtonTail:        "KEYCODE_STYLUS_BUTTON_TAIL",
+		KeycodeRecentApps:              "KEYCODE_RECENT_APPS",
+		KeycodeMacro4:                  "KEYCODE_MACRO_4",
+		KeycodeScreenshot:              "KEYCODE_SCREENSHOT",
+		Keycode(1000):                  "1000",
+	}
+	for k, want := range tests {
+		if got := k.String(); got != want {
+			t.Fatalf("Keycode(%d).String() = %q, want %q", int(k), got, want)
+		}
+	}
+	if KeycodeA != 29 || KeycodeEnter != 66 || KeycodeAppSwitch != 187 || KeycodeNumpad9 != 153 ||
+		KeycodeRecentApps != 312 || KeycodeScreenshot != 318 {
+		t.Fatal("keycode values do not match android.view.KeyEvent")
+	}
+}
+
+func TestKeyInput(t *testing.T) {
+	tests := []struct {
+		name string
+		call func(Device) error
+		want []string
+	}{
+		{"keyevent", func(d Device) error { return d.KeyEvent(context.Background(), KeycodeEnter) }, []string{"keyevent", "66"}},
+		{"batch", func(d Device) error {
+			return d.KeyEvents(context.Background(), KeycodeH, KeycodeI, KeycodeEnter)
+		}, []string{"keyevent", "36", "37", "66"}},
+		{"repeat", func(d Device) error {
+			return d.RepeatKey(context.Background(), KeycodeDel, 3)
+		}, []string{"keyevent", "67", "67", "67"}},
+		{"longpress", func(d Device) error {
+			return d.LongPressKey(context.Background(), KeycodePower)
+		}, []string{"keyevent", "--longpress", "26"}},
+		{"combination", func(d Device) error {
+			return d.KeyCombination(context.Background(), MetaCtrl|MetaShift, KeycodeZ)
+		}, []string{"keycombination", "113", "59", "54"}},
+		{"names", func(d Device) error {
+			return d.KeyEventNames(context.Background(), "KEYCODE_HOME", "66")
+		}, []string{"keyevent", "KEYCODE_HOME", "66"}},
+		{"back", func(d Device) error { return d.GoBack(context.Background()) }, []string{"keyevent", "4"}},
+		{"switch app", func(d Device) error { return d.SwitchApp(context.Background()) }, []string{"keyevent", "187"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, argsFile := fakeADB(t, "", "", 0)
+			if err := tt.call(c.Device("S")); err != nil {
+				t.Fatalf("%s error = %v", tt.name, err)
+			}
+			want := append([]string{"-s", "S", "shell", "input"}, tt.want...)
+			if got := readArgs(t, argsFile); !reflect.DeepEqual(got, want) {
+				t.Fatalf("args = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestKeyEvents_Empty(t *testing.T) {
+	c, calls := fakeADBSeq(t, fakeResponse{})
+	if err := c.Device("S").RepeatKey(context.Background(), KeycodeDel, 0); err != nil {
+		t.Fatalf("RepeatKey(0) error = %v", err)
+	}
+	if n := len(calls()); n != 0 {
+		t.Fatalf("RepeatKey(0) made %d calls, want 0", n)
+	}
+}
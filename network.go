@@ -0,0 +1,168 @@
+package adb
+
+import (
+	"context"
+	"net/netip"
+	"regexp"
+	"strings"
+)
+
+// SetWiFi turns wifi on or off, equivalent to
+// `adb shell svc wifi enable|disable`.
+func (d Device) SetWiFi(ctx context.Context, enabled bool) error {
+	return d.execChecked(ctx, "shell", "svc", "wifi", enableArg(enabled))
+}
+
+// SetMobileData turns mobile data on or off, equivalent to
+// `adb shell svc data enable|disable`.
+func (d Device) SetMobileData(ctx context.Context, enabled bool) error {
+	return d.execChecked(ctx, "shell", "svc", "data", enableArg(enabled))
+}
+
+func enableArg(enabled bool) string {
+	if enabled {
+		return "enable"
+	}
+	return "disable"
+}
+
+// SetAirplaneMode turns airplane mode on or off, equivalent to
+// `adb shell cmd connectivity airplane-mode enable|disable` (Android 10+).
+// On older releases it falls back to setting airplane_mode_on and sending the
+// AIRPLANE_MODE broadcast, which Android 7-9 only permit on rooted devices.
+func (d Device) SetAirplaneMode(ctx context.Context, enabled bool) error {
+	args := []string{"cmd", "connectivity", "airplane-mode", enableArg(enabled)}
+	res, err := d.Shell(ctx, args[0], args[1:]...)
+	if err != nil {
+		return err
+	}
+	if !cmdUnsupported(res) {
+		return d.cmdFailure(res, args...)
+	}
+	value := "0"
+	if enabled {
+		value = "1"
+	}
+	if err := d.Settings(SettingsGlobal).Put(ctx, "airplane_mode_on", value); err != nil {
+		return err
+	}
+	intent := Intent{Action: "android.intent.action.AIRPLANE_MODE"}.WithBool("state", enabled)
+	return d.SendBroadcast(ctx, intent)
+}
+
+// AirplaneMode reports whether airplane mode is on, read from the
+// airplane_mode_on global setting.
+func (d Device) AirplaneMode(ctx context.Context) (bool, error) {
+	v, err := d.Settings(SettingsGlobal).Get(ctx, "airplane_mode_on")
+	return v == "1", err
+}
+
+// Connectivity describes the device's default network, as chosen by the
+// connectivity service.
+type Connectivity struct {
+	// Connected reports whether there is a default network.
+	Connected bool
+	// Transports lists the default network's transports, for example
+	// ["WIFI"] or ["CELLULAR"]; a VPN adds "VPN".
+	Transports []string
+	// Validated reports whether the system has verified that the default
+	// network reaches the internet.
+	Validated bool
+}
+
+// Connectivity returns the state of the default network, parsed from
+// `adb shell dumpsys connectivity`.
+func (d Device) Connectivity(ctx context.Context) (Connectivity, error) {
+	res, err := d.client.run(ctx, "-s", d.serial, "shell", "dumpsys", "connectivity")
+	if err != nil {
+		return Connectivity{}, err
+	}
+	return parseConnectivity(res.StdoutString()), nil
+}
+
+var (
+	reDefaultNetwork = regexp.MustCompile(`Active default network: (\d+)`)
+	reNetworkAgent   = regexp.MustCompile(`NetworkAgentInfo\s*\{.*?\bnetwork\{(\d+)\}`)
+	reTransports     = regexp.MustCompile(`Transports: (\S+)`)
+)
+
+func parseConnectivity(out string) Connectivity {
+	var c Connectivity
+	m := reDefaultNetwork.FindStringSubmatch(out)
+	if m == nil {
+		return c
+	}
+	c.Connected = true
+	for line := range strings.SplitSeq(out, "\n") {
+		agent := reNetworkAgent.FindStringSubmatch(line)
+		if agent == nil || agent[1] != m[1] {
+			continue
+		}
+		if t := reTransports.FindStringSubmatch(line); t != nil {
+			c.Transports = strings.Split(t[1], "|")
+		}
+		c.Validated = strings.Contains(line, "VALIDATED")
+		break
+	}
+	return c
+}
+
+// NetInterface is a network interface and its addresses.
+type NetInterface struct {
+	// Name is the interface name, for example "wlan0".
+	Name string
+	// Up reports whether the interface is administratively up.
+	Up bool
+	// MAC is the hardware address, if the interface has one.
+	MAC string
+	// Addrs are the interface's IPv4 and IPv6 addresses with their prefix
+	// lengths.
+	Addrs []netip.Prefix
+}
+
+// NetInterfaces returns the device's network interfaces and their
+// addresses, parsed from `adb shell ip addr`.
+func (d Device) NetInterfaces(ctx context.Context) ([]NetInterface, error) {
+	res, err := d.client.run(ctx, "-s", d.serial, "shell", "ip", "addr")
+	if err != nil {
+		return nil, err
+	}
+	return parseIPAddr(res.StdoutString()), nil
+}
+
+// reIPLink matches an interface header such as
+// "2: wlan0: <BROADCAST,MULTICAST,UP,LOWER_UP> mtu 1500 ...".
+var reIPLink = regexp.MustCompile(`^\d+:\s+([^:@\s]+)(?:@\S+)?:\s+<([^>]*)>`)
+
+func parseIPAddr(out string) []NetInterface {
+	var ifaces []NetInterface
+	for line := range strings.SplitSeq(out, "\n") {
+		if m := reIPLink.FindStringSubmatch(line); m != nil {
+			ifaces = append(ifaces, NetInterface{
+				Name: m[1],
+				Up:   strings.Contains(","+m[2]+",", ",UP,"),
+			})
+			continue
+		}
+		if len(ifaces) == 0 {
+			continue
+		}
+		iface := &ifaces[len(ifaces)-1]
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+		switch fields[0] {
+		case "link/ether":
+			iface.MAC = fields[1]
+		case "inet", "inet6":
+			if p, err := netip.ParsePrefix(fields[1]); err == nil {
+				iface.Addrs = append(iface.Addrs, p)
+			} else if a, err := netip.ParseAddr(fields[1]); err == nil {
+				// Point-to-point links print "inet A peer B/N".
+				iface.Addrs = append(iface.Addrs, netip.PrefixFrom(a, a.BitLen()))
+			}
+		}
+	}
+	return ifaces
+}
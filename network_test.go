@@ -0,0 +1,166 @@
+package adb
+
+import (
+	"context"
+	"errors"
+	"net/netip"
+	"reflect"
+	"testing"
+)
+
+func TestNetworkToggles(t *testing.T) {
+	tests := []struct {
+		name string
+		call func(Device) error
+		want []string
+	}{
+		{"wifi on", func(d Device) error { return d.SetWiFi(context.Background(), true) }, []string{"svc", "wifi", "enable"}},
+		{"wifi off", func(d Device) error { return d.SetWiFi(context.Background(), false) }, []string{"svc", "wifi", "disable"}},
+		{"data off", func(d Device) error { return d.SetMobileData(context.Background(), false) }, []string{"svc", "data", "disable"}},
+		{"airplane", func(d Device) error {
+			return d.SetAirplaneMode(context.Background(), true)
+		}, []string{"cmd", "connectivity", "airplane-mode", "enable"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, argsFile := fakeADB(t, "", "", 0)
+			if err := tt.call(c.Device("S")); err != nil {
+				t.Fatalf("%s error = %v", tt.name, err)
+			}
+			want := append([]string{"-s", "S", "shell"}, tt.want...)
+			if got := readArgs(t, argsFile); !reflect.DeepEqual(got, want) {
+				t.Fatalf("args = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestSetWiFi_Failure(t *testing.T) {
+	c, _ := fakeADB(t, "Error: java.lang.SecurityException: not allowed\n", "", 0)
+	if err := c.Device("S").SetWiFi(context.Background(), true); !errors.Is(err, ErrCommandFailed) {
+		t.Fatalf("SetWiFi() error = %v, want ErrCommandFailed", err)
+	}
+}
+
+func TestSetAirplaneMode_Fallback(t *testing.T) {
+	tests := []struct {
+		name        string
+		unsupported fakeResponse
+	}{
+		{"unknown command", fakeResponse{stdout: "Unknown command: airplane-mode\n", code: 255}},
+		// Pre-7 shells exit 0 even when cmd is missing.
+		{"cmd not found", fakeResponse{stdout: "/system/bin/sh: cmd: not found\n"}},
+		{"cmd inaccessible", fakeResponse{stdout: "/system/bin/sh: cmd: inaccessible or not found\n"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, calls := fakeADBSeq(t,
+				tt.unsupported,
+				fakeResponse{},
+				fakeResponse{stdout: "Broadcasting: Intent { act=android.intent.action.AIRPLANE_MODE }\nBroadcast completed: result=0\n"},
+			)
+			if err := c.Device("S").SetAirplaneMode(context.Background(), false); err != nil {
+				t.Fatalf("SetAirplaneMode() error = %v", err)
+			}
+			want := [][]string{
+				{"-s", "S", "shell", "cmd", "connectivity", "airplane-mode", "disable"},
+				{"-s", "S", "shell", "settings", "put", "global", "airplane_mode_on", "0"},
+				{"-s", "S", "shell", "am", "broadcast", "-a", "android.intent.action.AIRPLANE_MODE", "--ez", "state", "false"},
+			}
+			if got := calls(); !reflect.DeepEqual(got, want) {
+				t.Fatalf("SetAirplaneMode() calls = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestSetAirplaneMode_Failure(t *testing.T) {
+	c, calls := fakeADBSeq(t, fakeResponse{
+		stderr: "Exception occurred while executing 'airplane-mode':\njava.lang.SecurityException: Permission denial\n",
+		code:   255,
+	})
+	if err := c.Device("S").SetAirplaneMode(context.Background(), true); !errors.Is(err, ErrCommandFailed) {
+		t.Fatalf("SetAirplaneMode() error = %v, want ErrCommandFailed", err)
+	}
+	// A real failure must not fall back to the settings path.
+	if n := len(calls()); n != 1 {
+		t.Fatalf("SetAirplaneMode() calls = %d, want 1", n)
+	}
+}
+
+func TestAirplaneMode(t *testing.T) {
+	c, _ := fakeADB(t, "1\n", "", 0)
+	if on, err := c.Device("S").AirplaneMode(context.Background()); err != nil || !on {
+		t.Fatalf("AirplaneMode() = %v, %v; want true", on, err)
+	}
+}
+
+func TestConnectivity(t *testing.T) {
+	out := `NetworkProviders for:
+Active default network: 101
+
+Current Networks:
+  NetworkAgentInfo{network{100}  handle{432902426637}  ni{MOBILE[LTE] CONNECTED}  nc{[ Transports: CELLULAR Capabilities: INTERNET&NOT_RESTRICTED&TRUSTED&VALIDATED ]}}
+  NetworkAgentInfo{network{101}  handle{437197393933}  ni{WIFI CONNECTED extra: }  nc{[ Transports: WIFI Capabilities: NOT_METERED&INTERNET&NOT_RESTRICTED&TRUSTED&VALIDATED ]}}
+`
+	c, argsFile := fakeADB(t, out, "", 0)
+	got, err := c.Device("S").Connectivity(context.Background())
+	if err != nil {
+		t.Fatalf("Connectivity() error = %v", err)
+	}
+	want := Connectivity{Connected: true, Transports: []string{"WIFI"}, Validated: true}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("Connectivity() = %+v, want %+v", got, want)
+	}
+	if args, want := readArgs(t, argsFile), []string{"-s", "S", "shell", "dumpsys", "connectivity"}; !reflect.DeepEqual(args, want) {
+		t.Fatalf("Connectivity() args = %v, want %v", args, want)
+	}
+	// Android 10 and earlier print the NetworkInfo before the network id.
+	legacy := `Active default network: 100
+
+Current Networks:
+  NetworkAgentInfo{ ni{[type: WIFI[], state: CONNECTED/CONNECTED, reason: (unspecified), extra: "Home", failover: false, available: true, roaming: false]}  network{100}  nethandle{432902426637}  lp{{InterfaceName: wlan0 LinkAddresses: [ 192.168.1.20/24 ]}}  nc{[ Transports: WIFI Capabilities: NOT_METERED&INTERNET&NOT_RESTRICTED&TRUSTED&NOT_VPN&VALIDATED&NOT_ROAMING]}  Score{60}  everValidated{true}  lastValidated{true}}
+`
+	if got := parseConnectivity(legacy); !reflect.DeepEqual(got, want) {
+		t.Fatalf("parseConnectivity(legacy) = %+v, want %+v", got, want)
+	}
+	if got := parseConnectivity("Active default network: none\n"); got.Connected {
+		t.Fatalf("parseConnectivity(none) = %+v, want disconnected", got)
+	}
+}
+
+func TestNetInterfaces(t *testing.T) {
+	out := `1: lo: <LOOPBACK,UP,LOWER_UP> mtu 65536 qdisc noqueue state UNKNOWN group default qlen 1000
+    link/loopback 00:00:00:00:00:00 brd 00:00:00:00:00:00
+    inet 127.0.0.1/8 scope host lo
+       valid_lft forever preferred_lft forever
+    inet6 ::1/128 scope host
+       valid_lft forever preferred_lft forever
+3: eth0@if4: <BROADCAST,MULTICAST> mtu 1500 qdisc noop state DOWN group default qlen 1000
+    link/ether 52:54:00:12:34:56 brd ff:ff:ff:ff:ff:ff
+4: wlan0: <BROADCAST,MULTICAST,UP,LOWER_UP> mtu 1500 qdisc mq state UP group default qlen 1000
+    link/ether 02:15:b2:00:00:00 brd ff:ff:ff:ff:ff:ff
+    inet 10.0.2.16/24 brd 10.0.2.255 scope global wlan0
+       valid_lft forever preferred_lft forever
+    inet6 fe80::15:b2ff:fe00:0/64 scope link
+       valid_lft forever preferred_lft forever
+5: rmnet0: <POINTOPOINT,UP,LOWER_UP> mtu 1500 qdisc fq_codel state UNKNOWN
+    inet 10.64.1.2 peer 10.64.1.1/32 scope global rmnet0
+`
+	c, _ := fakeADB(t, out, "", 0)
+	got, err := c.Device("S").NetInterfaces(context.Background())
+	if err != nil {
+		t.Fatalf("NetInterfaces() error = %v", err)
+	}
+	want := []NetInterface{
+		{Name: "lo", Up: true, Addrs: []netip.Prefix{netip.MustParsePrefix("127.0.0.1/8"), netip.MustParsePrefix("::1/128")}},
+		{Name: "eth0", MAC: "52:54:00:12:34:56"},
+		{Name: "wlan0", Up: true, MAC: "02:15:b2:00:00:00", Addrs: []netip.Prefix{
+			netip.MustParsePrefix("10.0.2.16/24"), netip.MustParsePrefix("fe80::15:b2ff:fe00:0/64"),
+		}},
+		{Name: "rmnet0", Up: true, Addrs: []netip.Prefix{netip.MustParsePrefix("10.64.1.2/32")}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("NetInterfaces() = %+v, want %+v", got, want)
+	}
+}
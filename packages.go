@@ -0,0 +1,397 @@
+package adb
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Package is an installed package as reported by `pm list packages`. Fields
+// other than Name are only populated when requested through
+// [PackageListOptions].
+type Package struct {
+	Name string
+	// APKPath is the path of the base APK on the device (-f).
+	APKPath string
+	// Installer is the package that installed this one (-i), or "" when
+	// unknown (for example sideloaded or preinstalled apps).
+	Installer string
+	// UID is the Linux user id the package runs as (-U).
+	UID int
+	// VersionCode is the package's version code (--show-versioncode).
+	VersionCode int64
+}
+
+// PackageListOptions selects which packages [Device.Packages] lists and which
+// metadata it reports. The zero value lists every package by name.
+type PackageListOptions struct {
+	// APKPath reports each package's APK path (-f).
+	APKPath bool
+	// Installer reports each package's installer (-i).
+	Installer bool
+	// UID reports each package's uid (-U).
+	UID bool
+	// VersionCode reports each package's version code (--show-versioncode).
+	VersionCode bool
+	// ThirdParty lists only third-party packages (-3).
+	ThirdParty bool
+	// System lists only system packages (-s).
+	System bool
+	// Disabled lists only disabled packages (-d).
+	Disabled bool
+	// Enabled lists only enabled packages (-e).
+	Enabled bool
+	// User lists packages for the given user (--user): a user id, "current",
+	// or "all". Empty uses the pm default.
+	User string
+	// Filter lists only packages whose name contains the given text.
+	Filter string
+}
+
+func (o PackageListOptions) args() []string {
+	args := []string{"shell", "pm", "list", "packages"}
+	for _, f := range []struct {
+		set  bool
+		flag string
+	}{
+		{o.APKPath, "-f"},
+		{o.Installer, "-i"},
+		{o.UID, "-U"},
+		{o.VersionCode, "--show-versioncode"},
+		{o.ThirdParty, "-3"},
+		{o.System, "-s"},
+		{o.Disabled, "-d"},
+		{o.Enabled, "-e"},
+	} {
+		if f.set {
+			args = append(args, f.flag)
+		}
+	}
+	if o.User != "" {
+		args = append(args, "--user", shellQuote(o.User))
+	}
+	if o.Filter != "" {
+		args = append(args, shellQuote(o.Filter))
+	}
+	return args
+}
+
+// ListPackages returns the installed package names, equivalent to
+// `adb shell pm list packages`. Use [Device.Packages] to filter the list or
+// obtain package metadata.
+func (d Device) ListPackages(ctx context.Context) ([]string, error) {
+	pkgs, err := d.Packages(ctx, PackageListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, len(pkgs))
+	for i, p := range pkgs {
+		names[i] = p.Name
+	}
+	return names, nil
+}
+
+// Packages lists installed packages, equivalent to
+// `adb shell pm list packages` with the flags selected by opts.
+func (d Device) Packages(ctx context.Context, opts PackageListOptions) ([]Package, error) {
+	res, err := d.runChecked(ctx, opts.args()...)
+	if err != nil {
+		return nil, err
+	}
+	return parsePackages(res.StdoutString()), nil
+}
+
+// parsePackages parses `pm list packages` output. Each line is
+// "package:[APK=]NAME" followed by optional space-separated metadata in either
+// "key:value" (versionCode, uid) or "key=value" (installer) form.
+func parsePackages(stdout string) []Package {
+	packages := []Package{}
+	for line := range strings.SplitSeq(stdout, "\n") {
+		rest, ok := strings.CutPrefix(strings.TrimSpace(line), "package:")
+		if !ok {
+			continue
+		}
+		fields := strings.Fields(rest)
+		if len(fields) == 0 {
+			continue
+		}
+		var p Package
+		// Package names cannot contain "=", but APK paths can (the
+		// randomized install directories are base64), so split on the last.
+		if i := strings.LastIndex(fields[0], "="); i >= 0 {
+			p.APKPath, p.Name = fields[0][:i], fields[0][i+1:]
+		} else {
+			p.Name = fields[0]
+		}
+		if p.Name == "" {
+			continue
+		}
+		for _, f := range fields[1:] {
+			i := strings.IndexAny(f, ":=")
+			if i < 0 {
+				continue
+			}
+			key, value := f[:i], f[i+1:]
+			switch key {
+			case "installer":
+				if value != "null" {
+					p.Installer = value
+				}
+			case "uid":
+				p.UID, _ = strconv.Atoi(value)
+			case "versionCode":
+				p.VersionCode, _ = strconv.ParseInt(value, 10, 64)
+			}
+		}
+		packages = append(packages, p)
+	}
+	return packages
+}
+
+// EnabledState is a package's enabled setting, mirroring Android's
+// PackageManager.COMPONENT_ENABLED_STATE_* constants.
+type EnabledState int
+
+const (
+	// ComponentEnabledDefault uses the enabled state declared in the manifest.
+	ComponentEnabledDefault EnabledState = iota
+	// ComponentEnabled has been explicitly enabled.
+	ComponentEnabled
+	// ComponentDisabled has been explicitly disabled.
+	ComponentDisabled
+	// ComponentDisabledUser has been disabled by the user.
+	ComponentDisabledUser
+	// ComponentDisabledUntilUsed is disabled until the user first uses it.
+	ComponentDisabledUntilUsed
+)
+
+// String returns the name pm uses for the state.
+func (s EnabledState) String() string {
+	switch s {
+	case ComponentEnabledDefault:
+		return "default"
+	case ComponentEnabled:
+		return "enabled"
+	case ComponentDisabled:
+		return "disabled"
+	case ComponentDisabledUser:
+		return "disabled-user"
+	case ComponentDisabledUntilUsed:
+		return "disabled-until-used"
+	default:
+		return "unknown"
+	}
+}
+
+// Enabled reports whether the state leaves the package usable.
+func (s EnabledState) Enabled() bool {
+	return s == ComponentEnabledDefault || s == ComponentEnabled
+}
+
+// PackageInfo describes an installed package, as reported by
+// [Device.PackageInfo].
+type PackageInfo struct {
+	Name        string
+	VersionName string
+	VersionCode int64
+	MinSDK      int
+	TargetSDK   int
+	// FirstInstallTime and LastUpdateTime are reported by the device in its
+	// local time zone without an offset. They are interpreted in the zone
+	// named by the persist.sys.timezone property, or as UTC when that
+	// property is unset or names a zone the host's time zone database does
+	// not know.
+	FirstInstallTime time.Time
+	LastUpdateTime   time.Time
+	// Installer is the package that installed this one, or "" when unknown.
+	Installer string
+	// DataDir is the package's private data directory.
+	DataDir string
+	// CodePath is the directory containing the package's APKs.
+	CodePath string
+	// APKPaths lists the base APK followed by any split APKs.
+	APKPaths []string
+	// RequestedPermissions are the permissions declared in the manifest.
+	RequestedPermissions []string
+	// GrantedPermissions are the install-time and runtime permissions
+	// currently granted (runtime grants are those of the first user listed).
+	GrantedPermissions []string
+	// IntentActivities are the package's activities that declare at least
+	// one intent filter, taken from the activity resolver table. dumpsys
+	// does not list activities without an intent filter, so those are
+	// omitted.
+	IntentActivities []string
+	// EnabledState is the package's enabled setting for the first user listed.
+	EnabledState EnabledState
+}
+
+// PackageInfo returns details about an installed package, parsed from
+// `adb shell dumpsys package <pkg>` and `adb shell pm path <pkg>`. It returns
+// [ErrPackageNotFound] if the package is not installed.
+func (d Device) PackageInfo(ctx context.Context, pkg string) (PackageInfo, error) {
+	res, err := d.client.run(ctx, "-s", d.serial, "shell", "dumpsys", "package", shellQuote(pkg))
+	if err != nil {
+		return PackageInfo{}, err
+	}
+	zone, err := d.GetProp(ctx, "persist.sys.timezone")
+	if err != nil {
+		return PackageInfo{}, err
+	}
+	info, ok := parsePackageDump(res.StdoutString(), pkg, deviceLocation(zone))
+	if !ok {
+		return PackageInfo{}, fmt.Errorf("%w: %s", ErrPackageNotFound, pkg)
+	}
+	res, err = d.runChecked(ctx, "shell", "pm", "path", shellQuote(pkg))
+	if err != nil {
+		return PackageInfo{}, err
+	}
+	for line := range strings.SplitSeq(res.StdoutString(), "\n") {
+		if path, ok := strings.CutPrefix(strings.TrimSpace(line), "package:"); ok && path != "" {
+			info.APKPaths = append(info.APKPaths, path)
+		}
+	}
+	return info, nil
+}
+
+// dumpsysTime is the layout of timestamps in `dumpsys package`.
+const dumpsysTime = "2006-01-02 15:04:05"
+
+// deviceLocation returns the location named by the device's
+// persist.sys.timezone property, or UTC if zone is empty or unknown.
+func deviceLocation(zone string) *time.Location {
+	if zone == "" {
+		return time.UTC
+	}
+	loc, err := time.LoadLocation(zone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// parsePackageDump parses the output of `dumpsys package <pkg>`, reading
+// timestamps in loc. It reports false when the output contains no
+// "Package [pkg]" record.
+func parsePackageDump(out, pkg string, loc *time.Location) (PackageInfo, bool) {
+	info := PackageInfo{Name: pkg}
+	header := "Package [" + pkg + "]"
+	var (
+		found     bool
+		inPackage bool
+		inResolve bool
+		section   string
+		seenUser  bool
+		otherUser bool
+	)
+	activities := map[string]bool{}
+	for line := range strings.SplitSeq(out, "\n") {
+		trimmed := strings.TrimSpace(line)
+		indent := len(line) - len(strings.TrimLeft(line, " "))
+		switch {
+		case trimmed == "":
+			continue
+		case indent == 0:
+			// A new top-level table. Only the first package record (under
+			// "Packages:") is parsed; hidden system packages repeat it.
+			inResolve = trimmed == "Activity Resolver Table:"
+			inPackage = false
+			continue
+		case strings.HasPrefix(trimmed, "Package ["):
+			inPackage = !found && strings.HasPrefix(trimmed, header)
+			found = found || inPackage
+			section = ""
+			continue
+		}
+
+		if inResolve {
+			// "<hash> pkg/.Activity filter <hash>"
+			if f := strings.Fields(trimmed); len(f) >= 2 && strings.HasPrefix(f[1], pkg+"/") {
+				if !activities[f[1]] {
+					activities[f[1]] = true
+					info.IntentActivities = append(info.IntentActivities, f[1])
+				}
+			}
+			continue
+		}
+		if !inPackage {
+			continue
+		}
+
+		switch {
+		case strings.HasSuffix(trimmed, ":"):
+			// A nested list header such as "runtime permissions:".
+			section = trimmed
+			continue
+		case strings.HasPrefix(trimmed, "User ") && strings.Contains(trimmed, ":"):
+			// Per-user state. Only the first user's enabled state and runtime
+			// grants are reported.
+			section = ""
+			otherUser = seenUser
+			seenUser = true
+			if otherUser {
+				continue
+			}
+			for _, kv := range strings.Fields(trimmed) {
+				if v, ok := strings.CutPrefix(kv, "enabled="); ok {
+					n, _ := strconv.Atoi(v)
+					info.EnabledState = EnabledState(n)
+				}
+			}
+			continue
+		}
+
+		name, rest, _ := strings.Cut(trimmed, ":")
+		isPerm := name != "" && !strings.ContainsAny(name, " =")
+		switch {
+		case section == "requested permissions:" && isPerm:
+			info.RequestedPermissions = append(info.RequestedPermissions, name)
+			continue
+		case (section == "install permissions:" || section == "runtime permissions:") && isPerm:
+			if !otherUser && strings.Contains(rest, "granted=true") {
+				info.GrantedPermissions = append(info.GrantedPermissions, name)
+			}
+			continue
+		}
+		section = ""
+
+		for _, kv := range strings.Fields(trimmed) {
+			key, value, ok := strings.Cut(kv, "=")
+			if !ok {
+				continue
+			}
+			switch key {
+			case "versionCode":
+				info.VersionCode, _ = strconv.ParseInt(value, 10, 64)
+			case "minSdk":
+				info.MinSDK, _ = strconv.Atoi(value)
+			case "targetSdk":
+				info.TargetSDK, _ = strconv.Atoi(value)
+			case "versionName":
+				// Version names may contain spaces; take the rest of the line.
+				info.VersionName = strings.TrimPrefix(trimmed, "versionName=")
+			case "codePath":
+				info.CodePath = value
+			case "dataDir":
+				info.DataDir = value
+			case "installerPackageName":
+				if value != "null" {
+					info.Installer = value
+				}
+			case "firstInstallTime", "lastUpdateTime":
+				// The value is "date time", split across two fields.
+				t, err := time.ParseInLocation(dumpsysTime, strings.TrimPrefix(trimmed, key+"="), loc)
+				if err != nil {
+					continue
+				}
+				if key == "firstInstallTime" {
+					info.FirstInstallTime = t
+				} else {
+					info.LastUpdateTime = t
+				}
+			}
+		}
+	}
+	return info, found
+}
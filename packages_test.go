@@ -0,0 +1,163 @@
+package adb
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParsePackages_Metadata(t *testing.T) {
+	out := "package:/data/app/~~aGVsbG8=/com.example-d29ybGQ==/base.apk=com.example versionCode:42 installer=com.android.vending uid:10150\n" +
+		"package:/system/priv-app/Settings/Settings.apk=com.android.settings versionCode:34 installer=null uid:1000\n" +
+		"package:com.bare\n" +
+		"package:\n"
+	want := []Package{
+		{Name: "com.example", APKPath: "/data/app/~~aGVsbG8=/com.example-d29ybGQ==/base.apk", Installer: "com.android.vending", UID: 10150, VersionCode: 42},
+		{Name: "com.android.settings", APKPath: "/system/priv-app/Settings/Settings.apk", UID: 1000, VersionCode: 34},
+		{Name: "com.bare"},
+	}
+	if got := parsePackages(out); !reflect.DeepEqual(got, want) {
+		t.Fatalf("parsePackages() = %#v, want %#v", got, want)
+	}
+}
+
+func TestPackagesArgs(t *testing.T) {
+	c, argsFile := fakeADB(t, "package:com.example uid:10150\n", "", 0)
+	opts := PackageListOptions{APKPath: true, Installer: true, UID: true, VersionCode: true, ThirdParty: true, Enabled: true, User: "0", Filter: "example"}
+	got, err := c.Device("S").Packages(context.Background(), opts)
+	if err != nil {
+		t.Fatalf("Packages() error = %v", err)
+	}
+	if len(got) != 1 || got[0].UID != 10150 {
+		t.Fatalf("Packages() = %#v", got)
+	}
+	want := []string{"-s", "S", "shell", "pm", "list", "packages", "-f", "-i", "-U", "--show-versioncode", "-3", "-e", "--user", "0", "example"}
+	if args := readArgs(t, argsFile); !reflect.DeepEqual(args, want) {
+		t.Fatalf("Packages() args = %v, want %v", args, want)
+	}
+}
+
+const packageDump = `Activity Resolver Table:
+  Non-Data Actions:
+      android.intent.action.MAIN:
+        5b1c2d3 com.example/.MainActivity filter 8e9f0a1
+          Action: "android.intent.action.MAIN"
+      com.example.SHARE:
+        6c2d3e4 com.example/.ShareActivity filter 9f0a1b2
+        7d3e4f5 com.example/.MainActivity filter 0a1b2c3
+
+Key Set Manager:
+  [com.example]
+      Signing KeySets: 51
+
+Packages:
+  Package [com.example] (a1b2c3d):
+    userId=10150
+    pkg=Package{d4e5f6 com.example}
+    codePath=/data/app/~~abc==/com.example-xyz==
+    primaryCpuAbi=arm64-v8a
+    versionCode=42 minSdk=21 targetSdk=33
+    versionName=1.2.3 beta
+    splits=[base, config.arm64_v8a]
+    dataDir=/data/user/0/com.example
+    timeStamp=2023-05-01 10:00:00
+    firstInstallTime=2023-05-01 10:00:01
+    lastUpdateTime=2023-06-01 12:30:00
+    installerPackageName=com.android.vending
+    requested permissions:
+      android.permission.INTERNET
+      android.permission.CAMERA
+      android.permission.READ_MEDIA_IMAGES: restricted=true
+    install permissions:
+      android.permission.INTERNET: granted=true
+    User 0: ceDataInode=12345 installed=true hidden=false suspended=false stopped=false notLaunched=false enabled=3 instant=false virtual=false
+      gids=[3003]
+      runtime permissions:
+        android.permission.CAMERA: granted=true, flags=[ USER_SET ]
+        android.permission.READ_MEDIA_IMAGES: granted=false, flags=[ USER_SET ]
+      enabledComponents:
+        com.example.Hidden
+    User 10: ceDataInode=0 installed=true enabled=0
+      runtime permissions:
+        android.permission.READ_MEDIA_IMAGES: granted=true, flags=[ USER_SET ]
+
+Hidden system packages:
+  Package [com.example] (ffffff):
+    versionCode=1 minSdk=21 targetSdk=21
+`
+
+func TestParsePackageDump(t *testing.T) {
+	got, ok := parsePackageDump(packageDump, "com.example", time.UTC)
+	if !ok {
+		t.Fatal("parsePackageDump() found no package")
+	}
+	want := PackageInfo{
+		Name:                 "com.example",
+		VersionName:          "1.2.3 beta",
+		VersionCode:          42,
+		MinSDK:               21,
+		TargetSDK:            33,
+		FirstInstallTime:     time.Date(2023, 5, 1, 10, 0, 1, 0, time.UTC),
+		LastUpdateTime:       time.Date(2023, 6, 1, 12, 30, 0, 0, time.UTC),
+		Installer:            "com.android.vending",
+		DataDir:              "/data/user/0/com.example",
+		CodePath:             "/data/app/~~abc==/com.example-xyz==",
+		RequestedPermissions: []string{"android.permission.INTERNET", "android.permission.CAMERA", "android.permission.READ_MEDIA_IMAGES"},
+		GrantedPermissions:   []string{"android.permission.INTERNET", "android.permission.CAMERA"},
+		IntentActivities:     []string{"com.example/.MainActivity", "com.example/.ShareActivity"},
+		EnabledState:         ComponentDisabledUser,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("parsePackageDump() =\n%#v\nwant\n%#v", got, want)
+	}
+	if got.EnabledState.Enabled() {
+		t.Fatal("disabled-user state reported as enabled")
+	}
+}
+
+func TestPackageInfo(t *testing.T) {
+	c, calls := fakeADBSeq(t,
+		fakeResponse{stdout: packageDump},
+		fakeResponse{stdout: "Asia/Tokyo\n"},
+		fakeResponse{stdout: "package:/data/app/~~abc==/com.example-xyz==/base.apk\npackage:/data/app/~~abc==/com.example-xyz==/split_config.arm64_v8a.apk\n"},
+	)
+	info, err := c.Device("S").PackageInfo(context.Background(), "com.example")
+	if err != nil {
+		t.Fatalf("PackageInfo() error = %v", err)
+	}
+	if _, err := time.LoadLocation("Asia/Tokyo"); err == nil {
+		// 10:00:01 in Tokyo is 01:00:01 UTC.
+		if want := time.Date(2023, 5, 1, 1, 0, 1, 0, time.UTC); !info.FirstInstallTime.Equal(want) || info.FirstInstallTime.Location().String() != "Asia/Tokyo" {
+			t.Fatalf("PackageInfo().FirstInstallTime = %v, want %v in Asia/Tokyo", info.FirstInstallTime, want)
+		}
+	}
+	wantPaths := []string{"/data/app/~~abc==/com.example-xyz==/base.apk", "/data/app/~~abc==/com.example-xyz==/split_config.arm64_v8a.apk"}
+	if !reflect.DeepEqual(info.APKPaths, wantPaths) {
+		t.Fatalf("PackageInfo().APKPaths = %v, want %v", info.APKPaths, wantPaths)
+	}
+	wantCalls := [][]string{
+		{"-s", "S", "shell", "dumpsys", "package", "com.example"},
+		{"-s", "S", "shell", "getprop", "persist.sys.timezone"},
+		{"-s", "S", "shell", "pm", "path", "com.example"},
+	}
+	if got := calls(); !reflect.DeepEqual(got, wantCalls) {
+		t.Fatalf("PackageInfo() calls = %v, want %v", got, wantCalls)
+	}
+}
+
+func TestDeviceLocation(t *testing.T) {
+	for _, zone := range []string{"", "Not/AZone"} {
+		if got := deviceLocation(zone); got != time.UTC {
+			t.Fatalf("deviceLocation(%q) = %v, want UTC", zone, got)
+		}
+	}
+}
+
+func TestPackageInfo_NotFound(t *testing.T) {
+	c, _ := fakeADB(t, "Unable to find package: com.missing\n", "", 0)
+	if _, err := c.Device("S").PackageInfo(context.Background(), "com.missing"); !errors.Is(err, ErrPackageNotFound) {
+		t.Fatalf("PackageInfo() error = %v, want ErrPackageNotFound", err)
+	}
+}
@@ -0,0 +1,286 @@
+package adb
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MemInfo is a process's memory usage, parsed from the App Summary section of
+// `dumpsys meminfo <pkg>`. All sizes are in kilobytes. RSS values are only
+// reported on Android 10+ and are zero on older releases.
+type MemInfo struct {
+	// PID is the process the figures belong to.
+	PID int
+	// TotalPSS is the proportional set size: private memory plus an even
+	// share of memory shared with other processes.
+	TotalPSS int
+	// TotalRSS is the resident set size, counting shared memory in full.
+	TotalRSS int
+	// TotalSwapPSS is the proportional share of swapped-out memory.
+	TotalSwapPSS int
+	// JavaHeap is the PSS of the Java (ART) heap.
+	JavaHeap int
+	// NativeHeap is the PSS of the native (malloc) heap.
+	NativeHeap int
+	// Code is the PSS of mapped code: dex, oat, and shared libraries.
+	Code int
+	// Stack is the PSS of thread stacks.
+	Stack int
+	// Graphics is the PSS of GPU buffers and textures.
+	Graphics int
+	// PrivateOther is private memory not in any other category.
+	PrivateOther int
+	// System is shared memory attributed to the system, such as the zygote.
+	System int
+}
+
+// MemInfo returns the memory usage of a package's process, equivalent to
+// `adb shell dumpsys meminfo <pkg>`. When the package runs several processes
+// only the first is reported. It returns an error wrapping
+// [ErrProcessNotFound] if the package is not running.
+func (d Device) MemInfo(ctx context.Context, pkg string) (MemInfo, error) {
+	res, err := d.client.run(ctx, "-s", d.serial, "shell", "dumpsys", "meminfo", pkg)
+	if err != nil {
+		return MemInfo{}, err
+	}
+	m, ok := parseMemInfo(res.StdoutString())
+	if !ok {
+		return MemInfo{}, fmt.Errorf("%w: %s", ErrProcessNotFound, pkg)
+	}
+	return m, nil
+}
+
+var (
+	reMemInfoPID   = regexp.MustCompile(`\*\* MEMINFO in pid (\d+) `)
+	reMemSummary   = regexp.MustCompile(`^\s*([A-Za-z ]+):\s+(\d+)`)
+	reMemTotalPSS  = regexp.MustCompile(`TOTAL(?: PSS)?:\s+(\d+)`)
+	reMemTotalRSS  = regexp.MustCompile(`TOTAL RSS:\s+(\d+)`)
+	reMemTotalSwap = regexp.MustCompile(`TOTAL SWAP PSS:\s+(\d+)`)
+)
+
+// parseMemInfo parses the first process in `dumpsys meminfo <pkg>` output. It
+// reports false when the output contains no process.
+func parseMemInfo(out string) (MemInfo, bool) {
+	var m MemInfo
+	match := reMemInfoPID.FindStringSubmatch(out)
+	if match == nil {
+		return m, false
+	}
+	m.PID, _ = strconv.Atoi(match[1])
+	// Skip to the first process's App Summary; later processes repeat it.
+	_, summary, ok := strings.Cut(out, "App Summary")
+	if !ok {
+		return m, true
+	}
+	summary, _, _ = strings.Cut(summary, "** MEMINFO")
+	for line := range strings.SplitSeq(summary, "\n") {
+		if strings.Contains(line, "TOTAL") {
+			m.TotalPSS = submatchInt(reMemTotalPSS, line)
+			m.TotalRSS = submatchInt(reMemTotalRSS, line)
+			m.TotalSwapPSS = submatchInt(reMemTotalSwap, line)
+			continue
+		}
+		match := reMemSummary.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+		kb, _ := strconv.Atoi(match[2])
+		switch match[1] {
+		case "Java Heap":
+			m.JavaHeap = kb
+		case "Native Heap":
+			m.NativeHeap = kb
+		case "Code":
+			m.Code = kb
+		case "Stack":
+			m.Stack = kb
+		case "Graphics":
+			m.Graphics = kb
+		case "Private Other":
+			m.PrivateOther = kb
+		case "System":
+			m.System = kb
+		}
+	}
+	return m, true
+}
+
+func submatchInt(re *regexp.Regexp, s string) int {
+	match := re.FindStringSubmatch(s)
+	if match == nil {
+		return 0
+	}
+	n, _ := strconv.Atoi(match[1])
+	return n
+}
+
+// cpuCounters is a snapshot of the CPU time consumed by a process and by the
+// whole system, in clock ticks.
+type cpuCounters struct {
+	pid   int
+	proc  uint64 // utime + stime of the process
+	total uint64 // sum of the aggregate "cpu" line of /proc/stat
+	cpus  int
+}
+
+func (d Device) cpuCounters(ctx context.Context, pkg string) (cpuCounters, error) {
+	pid, err := d.PidOf(ctx, pkg)
+	if err != nil {
+		return cpuCounters{}, err
+	}
+	res, err := d.client.run(ctx, "-s", d.serial, "shell", "cat", "/proc/stat", "/proc/"+strconv.Itoa(pid)+"/stat")
+	if err != nil {
+		return cpuCounters{}, err
+	}
+	c, ok := parseCPUCounters(res.StdoutString())
+	if !ok {
+		return cpuCounters{}, fmt.Errorf("%w: %s", ErrProcessNotFound, pkg)
+	}
+	c.pid = pid
+	return c, nil
+}
+
+// parseCPUCounters parses the concatenation of /proc/stat and
+// /proc/<pid>/stat.
+func parseCPUCounters(out string) (cpuCounters, bool) {
+	var c cpuCounters
+	var sawProc bool
+	for line := range strings.SplitSeq(out, "\n") {
+		switch {
+		case strings.HasPrefix(line, "cpu "):
+			for _, f := range strings.Fields(line)[1:] {
+				n, _ := strconv.ParseUint(f, 10, 64)
+				c.total += n
+			}
+		case strings.HasPrefix(line, "cpu"):
+			c.cpus++
+		case line != "" && line[0] >= '0' && line[0] <= '9':
+			// The command name is parenthesized and may contain spaces, so
+			// count fields from the closing parenthesis: utime and stime are
+			// fields 14 and 15 of the line.
+			i := strings.LastIndexByte(line, ')')
+			if i < 0 {
+				continue
+			}
+			fields := strings.Fields(line[i+1:])
+			if len(fields) < 13 {
+				continue
+			}
+			utime, _ := strconv.ParseUint(fields[11], 10, 64)
+			stime, _ := strconv.ParseUint(fields[12], 10, 64)
+			c.proc = utime + stime
+			sawProc = true
+		}
+	}
+	return c, sawProc && c.total > 0
+}
+
+// cpuPercent returns the CPU used by the process between two snapshots as a
+// percentage of one core, as top reports it: a process saturating two cores
+// uses 200%.
+func cpuPercent(before, after cpuCounters) float64 {
+	if after.total <= before.total || after.proc < before.proc {
+		return 0
+	}
+	perCore := float64(after.total-before.total) / float64(max(after.cpus, 1))
+	return float64(after.proc-before.proc) / perCore * 100
+}
+
+// CPUUsage measures the CPU used by a package's main process over interval
+// from /proc/<pid>/stat deltas. The result is a percentage of one core, as
+// top reports it, so a process saturating two cores uses 200%. It returns an
+// error wrapping [ErrProcessNotFound] if the package is not running or
+// restarts during the measurement.
+func (d Device) CPUUsage(ctx context.Context, pkg string, interval time.Duration) (float64, error) {
+	before, err := d.cpuCounters(ctx, pkg)
+	if err != nil {
+		return 0, err
+	}
+	timer := time.NewTimer(interval)
+	select {
+	case <-ctx.Done():
+		timer.Stop()
+		return 0, ctx.Err()
+	case <-timer.C:
+	}
+	after, err := d.cpuCounters(ctx, pkg)
+	if err != nil {
+		return 0, err
+	}
+	if after.pid != before.pid {
+		return 0, fmt.Errorf("%w: %s restarted", ErrProcessNotFound, pkg)
+	}
+	return cpuPercent(before, after), nil
+}
+
+// ResourceSample is one point of the time series streamed by
+// [Device.SampleResources].
+type ResourceSample struct {
+	// Time is when the sample was taken.
+	Time time.Time
+	// Mem is the process's memory usage.
+	Mem MemInfo
+	// CPU is the CPU used since the previous sample, as a percentage of one
+	// core. It is zero for the first sample after the process (re)starts.
+	CPU float64
+	// Err reports why the sample could not be taken, for example because the
+	// process is not running; the other fields are then unset. Sampling
+	// continues after errors.
+	Err error
+}
+
+// SampleResources samples a package's memory and CPU usage every interval and
+// streams the samples until ctx is cancelled, after which the channel is
+// closed. Each sample costs three adb round trips, so intervals much shorter
+// than a second are not meaningful. The caller must drain the channel.
+//
+// A non-positive interval yields a single sample whose Err is
+// [ErrInvalidInterval], followed by the channel closing.
+func (d Device) SampleResources(ctx context.Context, pkg string, interval time.Duration) <-chan ResourceSample {
+	if interval <= 0 {
+		ch := make(chan ResourceSample, 1)
+		ch <- ResourceSample{Time: time.Now(), Err: fmt.Errorf("%w: %v", ErrInvalidInterval, interval)}
+		close(ch)
+		return ch
+	}
+	ch := make(chan ResourceSample)
+	go func() {
+		defer close(ch)
+		prev, prevErr := d.cpuCounters(ctx, pkg)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+			s := ResourceSample{Time: time.Now()}
+			cur, cpuErr := d.cpuCounters(ctx, pkg)
+			err := cpuErr
+			if err == nil {
+				if prevErr == nil && cur.pid == prev.pid {
+					s.CPU = cpuPercent(prev, cur)
+				}
+				s.Mem, err = d.MemInfo(ctx, pkg)
+			}
+			prev, prevErr = cur, cpuErr
+			if err != nil {
+				if ctx.Err() != nil {
+					return
+				}
+				s = ResourceSample{Time: s.Time, Err: err}
+			}
+			select {
+			case ch <- s:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return ch
+}
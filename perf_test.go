@@ -0,0 +1,168 @@
+package adb
+
+import (
+	"context"
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+	"time"
+)
+
+const memInfoDump = `Applications Memory Usage (in Kilobytes):
+Uptime: 1187563 Realtime: 1187563
+
+** MEMINFO in pid 4321 [com.example] **
+                   Pss  Private  Private  SwapPss      Rss     Heap     Heap     Heap
+                 Total    Dirty    Clean    Dirty    Total     Size    Alloc     Free
+                ------   ------   ------   ------   ------   ------   ------   ------
+  Native Heap    10468    10408        0        0    12000    20480    14462     6017
+  Dalvik Heap     2823     2780        0        0     5000     9010     4505     4505
+        TOTAL    28516    20000     3000        12    61000    29490    18967    10522
+
+ App Summary
+                       Pss(KB)                        Rss(KB)
+                        ------                         ------
+           Java Heap:     6480                          15000
+         Native Heap:    10408                          12000
+                Code:     3012                          20000
+               Stack:      128                            130
+            Graphics:     2004                           2004
+       Private Other:     1484
+              System:     5000
+             Unknown:                                    1000
+
+           TOTAL PSS:    28516            TOTAL RSS:    61000       TOTAL SWAP PSS:       12
+
+** MEMINFO in pid 4400 [com.example:remote] **
+ App Summary
+           Java Heap:     999
+           TOTAL PSS:     999            TOTAL RSS:     999       TOTAL SWAP PSS:        0
+`
+
+func TestMemInfo(t *testing.T) {
+	c, argsFile := fakeADB(t, memInfoDump, "", 0)
+	got, err := c.Device("S").MemInfo(context.Background(), "com.example")
+	if err != nil {
+		t.Fatalf("MemInfo() error = %v", err)
+	}
+	want := MemInfo{
+		PID:          4321,
+		TotalPSS:     28516,
+		TotalRSS:     61000,
+		TotalSwapPSS: 12,
+		JavaHeap:     6480,
+		NativeHeap:   10408,
+		Code:         3012,
+		Stack:        128,
+		Graphics:     2004,
+		PrivateOther: 1484,
+		System:       5000,
+	}
+	if got != want {
+		t.Fatalf("MemInfo() = %+v, want %+v", got, want)
+	}
+	wantArgs := []string{"-s", "S", "shell", "dumpsys", "meminfo", "com.example"}
+	if args := readArgs(t, argsFile); !reflect.DeepEqual(args, wantArgs) {
+		t.Fatalf("MemInfo() args = %v, want %v", args, wantArgs)
+	}
+}
+
+func TestMemInfo_Legacy(t *testing.T) {
+	// Android 9 and earlier report only PSS, totalled as "TOTAL:".
+	out := `** MEMINFO in pid 99 [com.example] **
+ App Summary
+                       Pss(KB)
+                        ------
+           Java Heap:     4000
+         Native Heap:     3000
+               TOTAL:    12000      TOTAL SWAP PSS:       7
+`
+	got, ok := parseMemInfo(out)
+	if !ok || got.PID != 99 || got.TotalPSS != 12000 || got.TotalRSS != 0 || got.TotalSwapPSS != 7 || got.JavaHeap != 4000 {
+		t.Fatalf("parseMemInfo() = %+v, %v", got, ok)
+	}
+}
+
+func TestMemInfo_NotRunning(t *testing.T) {
+	c, _ := fakeADB(t, "Applications Memory Usage (in Kilobytes):\nUptime: 1 Realtime: 1\nNo process found for: com.example\n", "", 0)
+	if _, err := c.Device("S").MemInfo(context.Background(), "com.example"); !errors.Is(err, ErrProcessNotFound) {
+		t.Fatalf("MemInfo() error = %v, want ErrProcessNotFound", err)
+	}
+}
+
+// procStat renders /proc/stat for four CPUs with the given aggregate ticks,
+// followed by a /proc/<pid>/stat line with the given utime and stime.
+func procStat(total, utime, stime string) string {
+	return "cpu  " + total + " 0 0 0 0 0 0 0 0 0\n" +
+		"cpu0 1 0 0 0 0 0 0 0 0 0\ncpu1 1 0 0 0 0 0 0 0 0 0\n" +
+		"cpu2 1 0 0 0 0 0 0 0 0 0\ncpu3 1 0 0 0 0 0 0 0 0 0\n" +
+		"intr 1 2 3\nctxt 100\n" +
+		"4321 (com.example (main)) S 600 600 0 0 -1 1077952832 9000 0 12 0 " + utime + " " + stime + " 0 0 10 -10 40 0 5000\n"
+}
+
+func TestCPUUsage(t *testing.T) {
+	c, calls := fakeADBSeq(t,
+		fakeResponse{stdout: "4321\n"},
+		fakeResponse{stdout: procStat("1000", "100", "50")},
+		fakeResponse{stdout: "4321\n"},
+		// 400 ticks across 4 CPUs is 100 ticks per core; the process used 150.
+		fakeResponse{stdout: procStat("1400", "200", "100")},
+	)
+	got, err := c.Device("S").CPUUsage(context.Background(), "com.example", 10*time.Millisecond)
+	if err != nil {
+		t.Fatalf("CPUUsage() error = %v", err)
+	}
+	if math.Abs(got-150) > 1e-9 {
+		t.Fatalf("CPUUsage() = %v, want 150", got)
+	}
+	want := []string{"-s", "S", "shell", "cat", "/proc/stat", "/proc/4321/stat"}
+	if got := calls()[1]; !reflect.DeepEqual(got, want) {
+		t.Fatalf("CPUUsage() args = %v, want %v", got, want)
+	}
+}
+
+func TestCPUUsage_NotRunning(t *testing.T) {
+	c, _ := fakeADB(t, "", "", 1)
+	if _, err := c.Device("S").CPUUsage(context.Background(), "com.example", time.Millisecond); !errors.Is(err, ErrProcessNotFound) {
+		t.Fatalf("CPUUsage() error = %v, want ErrProcessNotFound", err)
+	}
+}
+
+func TestSampleResources(t *testing.T) {
+	c, _ := fakeADBSeq(t,
+		fakeResponse{stdout: "4321\n"},
+		fakeResponse{stdout: procStat("1000", "100", "50")},
+		fakeResponse{stdout: "4321\n"},
+		fakeResponse{stdout: procStat("1400", "120", "80")},
+		fakeResponse{stdout: memInfoDump},
+	)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	samples := c.Device("S").SampleResources(ctx, "com.example", 10*time.Millisecond)
+	s := <-samples
+	if s.Err != nil {
+		t.Fatalf("sample error = %v", s.Err)
+	}
+	if math.Abs(s.CPU-50) > 1e-9 || s.Mem.TotalPSS != 28516 || s.Time.IsZero() {
+		t.Fatalf("sample = %+v", s)
+	}
+	cancel()
+	for range samples {
+		// Drain until the sampler closes the channel.
+	}
+}
+
+func TestSampleResources_InvalidInterval(t *testing.T) {
+	c, calls := fakeADBSeq(t, fakeResponse{})
+	var got []ResourceSample
+	for s := range c.Device("S").SampleResources(context.Background(), "com.example", 0) {
+		got = append(got, s)
+	}
+	if len(got) != 1 || !errors.Is(got[0].Err, ErrInvalidInterval) {
+		t.Fatalf("samples = %+v, want one ErrInvalidInterval sample", got)
+	}
+	if n := len(calls()); n != 0 {
+		t.Fatalf("calls = %d, want 0", n)
+	}
+}
@@ -0,0 +1,161 @@
+package adb
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Process is one row of the device process table.
+type Process struct {
+	PID  int
+	PPID int
+	// User is the name of the process owner, for example "u0_a123".
+	User string
+	// VSZ is the virtual memory size in kilobytes.
+	VSZ int
+	// RSS is the resident set size in kilobytes.
+	RSS int
+	// State is the process state, for example "S" (sleeping) or "R"
+	// (running).
+	State string
+	// Name is the process name; for app processes this is the package name,
+	// optionally followed by ":<process>".
+	Name string
+}
+
+// processColumns are the `ps -o` columns requested from toybox ps. NAME must
+// stay last: it is the only column that may contain spaces.
+const processColumns = "PID,PPID,USER,VSZ,RSS,S,NAME"
+
+// Processes returns every process on the device, equivalent to
+// `adb shell ps -A -o PID,PPID,USER,VSZ,RSS,S,NAME`. On devices older than
+// Android 8, whose toolbox ps does not support those options, it falls back
+// to plain `ps`.
+func (d Device) Processes(ctx context.Context) ([]Process, error) {
+	res, err := d.client.run(ctx, "-s", d.serial, "shell", "ps", "-A", "-o", processColumns)
+	if err != nil {
+		return nil, err
+	}
+	procs := parseProcesses(res.StdoutString())
+	if len(procs) > 0 {
+		return procs, nil
+	}
+	// Toolbox ps treats unknown arguments as a name filter and prints only
+	// its header.
+	res, err = d.client.run(ctx, "-s", d.serial, "shell", "ps")
+	if err != nil {
+		return nil, err
+	}
+	return parseProcesses(res.StdoutString()), nil
+}
+
+// parseProcesses parses ps output using its header row, so both the toybox
+// layout and the toolbox layout ("USER PID PPID VSIZE RSS WCHAN PC NAME",
+// with an unlabelled state column before NAME) are understood.
+func parseProcesses(out string) []Process {
+	var header []string
+	var procs []Process
+	for line := range strings.SplitSeq(out, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		if header == nil {
+			if slices.Contains(fields, "PID") {
+				header = fields
+			}
+			continue
+		}
+		if len(fields) < len(header) {
+			continue
+		}
+		// NAME is last and may contain spaces; everything before it lines
+		// up with the header, except toolbox's unlabelled state column.
+		nameIdx := len(header) - 1
+		p := Process{Name: strings.Join(fields[nameIdx:], " ")}
+		if !slices.Contains(header, "S") && len(fields) > len(header) {
+			p.State = fields[nameIdx]
+			p.Name = strings.Join(fields[nameIdx+1:], " ")
+		}
+		for i, col := range header[:nameIdx] {
+			switch col {
+			case "PID":
+				p.PID, _ = strconv.Atoi(fields[i])
+			case "PPID":
+				p.PPID, _ = strconv.Atoi(fields[i])
+			case "USER":
+				p.User = fields[i]
+			case "VSZ", "VSIZE":
+				p.VSZ, _ = strconv.Atoi(fields[i])
+			case "RSS":
+				p.RSS, _ = strconv.Atoi(fields[i])
+			case "S":
+				p.State = fields[i]
+			}
+		}
+		procs = append(procs, p)
+	}
+	return procs
+}
+
+// PidOf returns the pid of the process named name (for an app's main
+// process, its package name), equivalent to `adb shell pidof <name>`. On
+// devices without pidof it searches [Device.Processes]. It returns an error
+// wrapping [ErrProcessNotFound] if no such process is running.
+func (d Device) PidOf(ctx context.Context, name string) (int, error) {
+	res, err := d.Shell(ctx, "pidof", name)
+	if err != nil {
+		return 0, err
+	}
+	// Devices before Android 7 do not report the exit status of shell
+	// commands, so also look for the shell's complaint.
+	if res.Code == 127 || strings.Contains(res.StdoutString()+res.StderrString(), "pidof: not found") {
+		procs, err := d.Processes(ctx)
+		if err != nil {
+			return 0, err
+		}
+		for _, p := range procs {
+			if p.Name == name {
+				return p.PID, nil
+			}
+		}
+		return 0, fmt.Errorf("%w: %s", ErrProcessNotFound, name)
+	}
+	fields := strings.Fields(res.StdoutString())
+	if len(fields) == 0 {
+		return 0, fmt.Errorf("%w: %s", ErrProcessNotFound, name)
+	}
+	pid, err := strconv.Atoi(fields[0])
+	if err != nil {
+		return 0, fmt.Errorf("%w: %s", ErrProcessNotFound, name)
+	}
+	return pid, nil
+}
+
+// Signal is a Linux signal number for [Device.KillProcess]. The numbers are
+// the device's, which may differ from the host's for some signals.
+type Signal int
+
+// Signals commonly sent to device processes.
+const (
+	SIGHUP  Signal = 1
+	SIGINT  Signal = 2
+	SIGQUIT Signal = 3
+	SIGKILL Signal = 9
+	SIGUSR1 Signal = 10
+	SIGUSR2 Signal = 12
+	SIGTERM Signal = 15
+	SIGCONT Signal = 18
+	SIGSTOP Signal = 19
+)
+
+// KillProcess sends sig to the process pid, equivalent to
+// `adb shell kill -<sig> <pid>`. Signalling app processes requires root (see
+// [Device.Root]); use [Device.ForceStop] or [Device.Kill] to stop an app
+// otherwise.
+func (d Device) KillProcess(ctx context.Context, pid int, sig Signal) error {
+	return d.exec(ctx, "shell", "kill", "-"+strconv.Itoa(int(sig)), strconv.Itoa(pid))
+}
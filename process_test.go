@@ -0,0 +1,95 @@
+package adb
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestProcesses(t *testing.T) {
+	out := `  PID  PPID USER           VSZ    RSS S NAME
+    1     0 root      10943472   8740 S init
+  612     1 root      14651772  98120 S zygote64
+ 4321   612 u0_a123   15103480 142036 S com.example
+ 4400   612 u0_a123   14902012  80012 R com.example:remote
+`
+	c, argsFile := fakeADB(t, out, "", 0)
+	got, err := c.Device("S").Processes(context.Background())
+	if err != nil {
+		t.Fatalf("Processes() error = %v", err)
+	}
+	wantArgs := []string{"-s", "S", "shell", "ps", "-A", "-o", "PID,PPID,USER,VSZ,RSS,S,NAME"}
+	if args := readArgs(t, argsFile); !reflect.DeepEqual(args, wantArgs) {
+		t.Fatalf("Processes() args = %v, want %v", args, wantArgs)
+	}
+	if len(got) != 4 {
+		t.Fatalf("Processes() returned %d rows, want 4", len(got))
+	}
+	want := Process{PID: 4400, PPID: 612, User: "u0_a123", VSZ: 14902012, RSS: 80012, State: "R", Name: "com.example:remote"}
+	if got[3] != want {
+		t.Fatalf("Processes()[3] = %+v, want %+v", got[3], want)
+	}
+}
+
+func TestProcesses_ToolboxFallback(t *testing.T) {
+	toolbox := `USER      PID   PPID  VSIZE  RSS   WCHAN              PC  NAME
+root      1     0     8904   788   SyS_epoll_ 0000000000 S /init
+u0_a45    2345  198   1523460 45032 SyS_epoll_ 0000000000 S com.example
+`
+	c, calls := fakeADBSeq(t,
+		fakeResponse{stdout: "USER      PID   PPID  VSIZE  RSS   WCHAN              PC  NAME\n"},
+		fakeResponse{stdout: toolbox},
+	)
+	got, err := c.Device("S").Processes(context.Background())
+	if err != nil {
+		t.Fatalf("Processes() error = %v", err)
+	}
+	if n := len(calls()); n != 2 {
+		t.Fatalf("Processes() made %d calls, want 2", n)
+	}
+	want := []Process{
+		{PID: 1, User: "root", VSZ: 8904, RSS: 788, State: "S", Name: "/init"},
+		{PID: 2345, PPID: 198, User: "u0_a45", VSZ: 1523460, RSS: 45032, State: "S", Name: "com.example"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("Processes() = %+v, want %+v", got, want)
+	}
+}
+
+func TestPidOf(t *testing.T) {
+	c, argsFile := fakeADB(t, "4321\n", "", 0)
+	pid, err := c.Device("S").PidOf(context.Background(), "com.example")
+	if err != nil || pid != 4321 {
+		t.Fatalf("PidOf() = %d, %v; want 4321", pid, err)
+	}
+	if got, want := readArgs(t, argsFile), []string{"-s", "S", "shell", "pidof", "com.example"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("PidOf() args = %v, want %v", got, want)
+	}
+
+	c, _ = fakeADB(t, "", "", 1)
+	if _, err := c.Device("S").PidOf(context.Background(), "com.example"); !errors.Is(err, ErrProcessNotFound) {
+		t.Fatalf("PidOf() error = %v, want ErrProcessNotFound", err)
+	}
+}
+
+func TestPidOf_WithoutPidof(t *testing.T) {
+	c, _ := fakeADBSeq(t,
+		fakeResponse{stdout: "/system/bin/sh: pidof: not found\n"},
+		fakeResponse{stdout: "USER PID PPID VSIZE RSS WCHAN PC NAME\nu0_a45 2345 198 1523460 45032 0 0 S com.example\n"},
+	)
+	pid, err := c.Device("S").PidOf(context.Background(), "com.example")
+	if err != nil || pid != 2345 {
+		t.Fatalf("PidOf() = %d, %v; want 2345", pid, err)
+	}
+}
+
+func TestKillProcess(t *testing.T) {
+	c, argsFile := fakeADB(t, "", "", 0)
+	if err := c.Device("S").KillProcess(context.Background(), 4321, SIGKILL); err != nil {
+		t.Fatalf("KillProcess() error = %v", err)
+	}
+	if got, want := readArgs(t, argsFile), []string{"-s", "S", "shell", "kill", "-9", "4321"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("KillProcess() args = %v, want %v", got, want)
+	}
+}
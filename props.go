@@ -0,0 +1,154 @@
+package adb
+
+import (
+	"context"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Properties is a snapshot of device system properties, keyed by property
+// name. The typed accessors return zero values for missing or malformed
+// properties.
+type Properties map[string]string
+
+// Props returns every system property in a single round trip, parsed from the
+// `[key]: [value]` listing printed by `adb shell getprop`. Use it instead of
+// repeated [Device.GetProp] calls when reading more than a few properties.
+func (d Device) Props(ctx context.Context) (Properties, error) {
+	res, err := d.client.run(ctx, "-s", d.serial, "shell", "getprop")
+	if err != nil {
+		return nil, err
+	}
+	return parseProps(res.StdoutString()), nil
+}
+
+// parseProps parses getprop output. Values may span several lines (for
+// example build descriptions containing newlines), in which case the entry
+// continues until a line ending in "]".
+func parseProps(out string) Properties {
+	props := Properties{}
+	var key string
+	var value strings.Builder
+	open := false
+	for line := range strings.SplitSeq(strings.ReplaceAll(out, "\r\n", "\n"), "\n") {
+		if open {
+			value.WriteByte('\n')
+			if rest, ok := strings.CutSuffix(line, "]"); ok {
+				value.WriteString(rest)
+				props[key] = value.String()
+				open = false
+			} else {
+				value.WriteString(line)
+			}
+			continue
+		}
+		rest, ok := strings.CutPrefix(line, "[")
+		if !ok {
+			continue
+		}
+		k, v, ok := strings.Cut(rest, "]: [")
+		if !ok {
+			continue
+		}
+		key = k
+		if v, ok = strings.CutSuffix(v, "]"); ok {
+			props[key] = v
+			continue
+		}
+		value.Reset()
+		value.WriteString(v)
+		open = true
+	}
+	return props
+}
+
+// SDKInt returns the API level (ro.build.version.sdk), or 0 if unknown.
+func (p Properties) SDKInt() int {
+	n, _ := strconv.Atoi(p["ro.build.version.sdk"])
+	return n
+}
+
+// Release returns the user-visible Android version
+// (ro.build.version.release), for example "14".
+func (p Properties) Release() string { return p["ro.build.version.release"] }
+
+// Manufacturer returns the device manufacturer (ro.product.manufacturer).
+func (p Properties) Manufacturer() string { return p["ro.product.manufacturer"] }
+
+// Model returns the device model (ro.product.model).
+func (p Properties) Model() string { return p["ro.product.model"] }
+
+// Fingerprint returns the build fingerprint (ro.build.fingerprint).
+func (p Properties) Fingerprint() string { return p["ro.build.fingerprint"] }
+
+// ABIs returns the supported ABIs in order of preference
+// (ro.product.cpu.abilist, falling back to ro.product.cpu.abi).
+func (p Properties) ABIs() []string {
+	if list := p["ro.product.cpu.abilist"]; list != "" {
+		return splitList(list)
+	}
+	return splitList(p["ro.product.cpu.abi"])
+}
+
+// BootCompleted reports whether the system has finished booting
+// (sys.boot_completed is "1").
+func (p Properties) BootCompleted() bool { return p["sys.boot_completed"] == "1" }
+
+// PropertyChangeKind describes how a property differs between two snapshots.
+type PropertyChangeKind int
+
+const (
+	// PropertyAdded is a property present only in the newer snapshot.
+	PropertyAdded PropertyChangeKind = iota
+	// PropertyRemoved is a property present only in the older snapshot.
+	PropertyRemoved
+	// PropertyChanged is a property whose value differs.
+	PropertyChanged
+)
+
+// String returns "added", "removed", or "changed".
+func (k PropertyChangeKind) String() string {
+	switch k {
+	case PropertyAdded:
+		return "added"
+	case PropertyRemoved:
+		return "removed"
+	case PropertyChanged:
+		return "changed"
+	default:
+		return "unknown"
+	}
+}
+
+// PropertyChange is a single difference reported by [DiffProperties].
+type PropertyChange struct {
+	Key  string
+	Kind PropertyChangeKind
+	// Old is the value in the older snapshot; empty for added properties.
+	Old string
+	// New is the value in the newer snapshot; empty for removed properties.
+	New string
+}
+
+// DiffProperties returns the differences between two snapshots, sorted by
+// property name. It returns nil if the snapshots are identical.
+func DiffProperties(older, newer Properties) []PropertyChange {
+	var changes []PropertyChange
+	for key, old := range older {
+		cur, ok := newer[key]
+		switch {
+		case !ok:
+			changes = append(changes, PropertyChange{Key: key, Kind: PropertyRemoved, Old: old})
+		case cur != old:
+			changes = append(changes, PropertyChange{Key: key, Kind: PropertyChanged, Old: old, New: cur})
+		}
+	}
+	for key, cur := range newer {
+		if _, ok := older[key]; !ok {
+			changes = append(changes, PropertyChange{Key: key, Kind: PropertyAdded, New: cur})
+		}
+	}
+	slices.SortFunc(changes, func(a, b PropertyChange) int { return strings.Compare(a.Key, b.Key) })
+	return changes
+}
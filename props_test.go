@@ -0,0 +1,77 @@
+package adb
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+const getpropOutput = `[dalvik.vm.heapsize]: [512m]
+[ro.build.fingerprint]: [google/sdk_gphone64_arm64/emu64a:14/UE1A.230829.036/10814564:userdebug/dev-keys]
+[ro.build.version.release]: [14]
+[ro.build.version.sdk]: [34]
+[ro.product.cpu.abilist]: [arm64-v8a,armeabi-v7a]
+[ro.product.manufacturer]: [Google]
+[ro.product.model]: [sdk_gphone64_arm64]
+[ro.test.multiline]: [first
+second]
+[sys.boot_completed]: [1]
+[vendor.empty]: []
+`
+
+func TestProps(t *testing.T) {
+	c, argsFile := fakeADB(t, getpropOutput, "", 0)
+	props, err := c.Device("S").Props(context.Background())
+	if err != nil {
+		t.Fatalf("Props() error = %v", err)
+	}
+	wantArgs := []string{"-s", "S", "shell", "getprop"}
+	if got := readArgs(t, argsFile); !reflect.DeepEqual(got, wantArgs) {
+		t.Fatalf("Props() args = %v, want %v", got, wantArgs)
+	}
+	if len(props) != 10 {
+		t.Fatalf("Props() parsed %d properties, want 10: %v", len(props), props)
+	}
+	if got := props["ro.test.multiline"]; got != "first\nsecond" {
+		t.Fatalf("multi-line value = %q", got)
+	}
+	if v, ok := props["vendor.empty"]; !ok || v != "" {
+		t.Fatalf("empty value = %q, %v", v, ok)
+	}
+	if props.SDKInt() != 34 || props.Release() != "14" || props.Manufacturer() != "Google" ||
+		props.Model() != "sdk_gphone64_arm64" || !props.BootCompleted() {
+		t.Fatalf("accessors returned unexpected values for %v", props)
+	}
+	if got := props.Fingerprint(); got != "google/sdk_gphone64_arm64/emu64a:14/UE1A.230829.036/10814564:userdebug/dev-keys" {
+		t.Fatalf("Fingerprint() = %q", got)
+	}
+	if got := props.ABIs(); !reflect.DeepEqual(got, []string{"arm64-v8a", "armeabi-v7a"}) {
+		t.Fatalf("ABIs() = %v", got)
+	}
+}
+
+func TestProperties_ZeroValues(t *testing.T) {
+	props := Properties{"ro.build.version.sdk": "S", "ro.product.cpu.abi": "x86"}
+	if props.SDKInt() != 0 || props.BootCompleted() {
+		t.Fatal("malformed or missing properties should return zero values")
+	}
+	if got := props.ABIs(); !reflect.DeepEqual(got, []string{"x86"}) {
+		t.Fatalf("ABIs() = %v, want fallback to ro.product.cpu.abi", got)
+	}
+}
+
+func TestDiffProperties(t *testing.T) {
+	older := Properties{"a": "1", "b": "2", "c": "3"}
+	newer := Properties{"a": "1", "b": "20", "d": "4"}
+	want := []PropertyChange{
+		{Key: "b", Kind: PropertyChanged, Old: "2", New: "20"},
+		{Key: "c", Kind: PropertyRemoved, Old: "3"},
+		{Key: "d", Kind: PropertyAdded, New: "4"},
+	}
+	if got := DiffProperties(older, newer); !reflect.DeepEqual(got, want) {
+		t.Fatalf("DiffProperties() = %v, want %v", got, want)
+	}
+	if got := DiffProperties(older, older); got != nil {
+		t.Fatalf("DiffProperties(same) = %v, want nil", got)
+	}
+}
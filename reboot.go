@@ -0,0 +1,106 @@
+package adb
+
+import (
+	"context"
+	"errors"
+)
+
+// RebootTarget selects the mode [Device.RebootTo] boots into.
+type RebootTarget string
+
+const (
+	// RebootSystem is a normal reboot into Android.
+	RebootSystem RebootTarget = ""
+	// RebootBootloader reboots into the bootloader.
+	RebootBootloader RebootTarget = "bootloader"
+	// RebootRecovery reboots into recovery.
+	RebootRecovery RebootTarget = "recovery"
+	// RebootSideload reboots into recovery and starts sideload mode.
+	RebootSideload RebootTarget = "sideload"
+	// RebootSideloadAutoReboot is like [RebootSideload], but reboots into
+	// Android once the sideload completes.
+	RebootSideloadAutoReboot RebootTarget = "sideload-auto-reboot"
+	// RebootFastboot reboots into userspace fastboot (fastbootd, Android 10+).
+	RebootFastboot RebootTarget = "fastboot"
+	// RebootUserspace restarts Android without rebooting the kernel
+	// (Android 11+ devices that support it).
+	RebootUserspace RebootTarget = "userspace"
+)
+
+// RebootTo reboots the device into target, equivalent to
+// `adb reboot [target]`. The handle must be reconnected afterward; see
+// [Device.RebootAndWait] for a normal reboot that does so.
+func (d Device) RebootTo(ctx context.Context, target RebootTarget) error {
+	if target == RebootSystem {
+		return d.Reboot(ctx)
+	}
+	return d.exec(ctx, "reboot", string(target))
+}
+
+// RebootAndWait reboots the device and blocks until it has fully booted: it
+// waits for the device to drop off adb, to come back (reconnecting network
+// devices with [Client.Connect]), and for both sys.boot_completed and
+// dev.bootcomplete to report 1. Bound the wait with a context deadline; a
+// cold boot commonly takes a minute or more.
+//
+// The returned handle addresses the rebooted device and should be used in
+// place of d.
+func (d Device) RebootAndWait(ctx context.Context) (Device, error) {
+	if err := d.Reboot(ctx); err != nil {
+		return Device{}, err
+	}
+	// The reboot command returns before adbd goes down; waiting for
+	// wait-for-device straight away would succeed against the old session.
+	err := poll(ctx, func(ctx context.Context) (bool, error) {
+		state, err := d.State(ctx)
+		if disconnected(err) {
+			return true, nil
+		}
+		return err == nil && state != "device", err
+	})
+	if err != nil {
+		return Device{}, err
+	}
+	if d.transport == Network {
+		err = poll(ctx, func(ctx context.Context) (bool, error) {
+			dev, err := d.client.Connect(ctx, d.serial)
+			if _, ok := errors.AsType[*CommandError](err); ok {
+				// adbd is not listening until the device is back up.
+				return false, nil
+			} else if err != nil {
+				return false, err
+			}
+			dev.target = d.target
+			d = dev
+			return true, nil
+		})
+		if err != nil {
+			return Device{}, err
+		}
+	}
+	if err := d.WaitForDevice(ctx); err != nil {
+		return Device{}, err
+	}
+	err = poll(ctx, func(ctx context.Context) (bool, error) {
+		for _, prop := range []string{"sys.boot_completed", "dev.bootcomplete"} {
+			v, err := d.GetProp(ctx, prop)
+			if disconnected(err) {
+				return false, nil
+			} else if err != nil || v != "1" {
+				return false, err
+			}
+		}
+		return true, nil
+	})
+	if err != nil {
+		return Device{}, err
+	}
+	return d, nil
+}
+
+// disconnected reports whether err indicates that the device is (perhaps
+// transiently) unreachable, as it is while rebooting.
+func disconnected(err error) bool {
+	return errors.Is(err, ErrDeviceNotFound) || errors.Is(err, ErrDeviceOffline) ||
+		errors.Is(err, ErrConnectionRefused)
+}
@@ -0,0 +1,81 @@
+package adb
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestRebootTo(t *testing.T) {
+	tests := []struct {
+		target RebootTarget
+		want   []string
+	}{
+		{RebootSystem, []string{"-s", "S", "reboot"}},
+		{RebootBootloader, []string{"-s", "S", "reboot", "bootloader"}},
+		{RebootSideloadAutoReboot, []string{"-s", "S", "reboot", "sideload-auto-reboot"}},
+		{RebootUserspace, []string{"-s", "S", "reboot", "userspace"}},
+	}
+	for _, tt := range tests {
+		c, argsFile := fakeADB(t, "", "", 0)
+		if err := c.Device("S").RebootTo(context.Background(), tt.target); err != nil {
+			t.Fatalf("RebootTo(%q) error = %v", tt.target, err)
+		}
+		if got := readArgs(t, argsFile); !reflect.DeepEqual(got, tt.want) {
+			t.Fatalf("RebootTo(%q) args = %v, want %v", tt.target, got, tt.want)
+		}
+	}
+}
+
+func TestRebootAndWait(t *testing.T) {
+	c, calls := fakeADBSeq(t,
+		fakeResponse{},                   // reboot
+		fakeResponse{stdout: "device\n"}, // get-state: still up
+		fakeResponse{stderr: "error: device 'S' not found\n", code: 1},
+		fakeResponse{},              // wait-for-device
+		fakeResponse{stdout: "\n"},  // sys.boot_completed: not yet
+		fakeResponse{stdout: "1\n"}, // sys.boot_completed
+		fakeResponse{stdout: "1\n"}, // dev.bootcomplete
+	)
+	d, err := c.Device("S").RebootAndWait(context.Background())
+	if err != nil {
+		t.Fatalf("RebootAndWait() error = %v", err)
+	}
+	if d.Serial() != "S" {
+		t.Fatalf("RebootAndWait() serial = %q", d.Serial())
+	}
+	want := [][]string{
+		{"-s", "S", "reboot"},
+		{"-s", "S", "get-state"},
+		{"-s", "S", "get-state"},
+		{"-s", "S", "wait-for-device"},
+		{"-s", "S", "shell", "getprop", "sys.boot_completed"},
+		{"-s", "S", "shell", "getprop", "sys.boot_completed"},
+		{"-s", "S", "shell", "getprop", "dev.bootcomplete"},
+	}
+	if got := calls(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("RebootAndWait() calls = %v, want %v", got, want)
+	}
+}
+
+func TestRebootAndWait_ReconnectsNetworkDevice(t *testing.T) {
+	c, calls := fakeADBSeq(t,
+		fakeResponse{}, // reboot
+		fakeResponse{stderr: "error: device offline\n", code: 1}, // get-state
+		fakeResponse{stdout: "failed to connect to '10.0.0.2:5555': Connection refused\n"},
+		fakeResponse{stdout: "connected to 10.0.0.2:5555\n"},
+		fakeResponse{},              // wait-for-device
+		fakeResponse{stdout: "1\n"}, // boot props
+	)
+	d, err := c.Device("10.0.0.2:5555").RebootAndWait(context.Background())
+	if err != nil {
+		t.Fatalf("RebootAndWait() error = %v", err)
+	}
+	if d.Transport() != Network || d.Serial() != "10.0.0.2:5555" {
+		t.Fatalf("RebootAndWait() device = %v %q", d.Transport(), d.Serial())
+	}
+	got := calls()
+	if len(got) != 7 || !reflect.DeepEqual(got[3], []string{"connect", "10.0.0.2:5555"}) {
+		t.Fatalf("RebootAndWait() calls = %v", got)
+	}
+}
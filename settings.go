@@ -0,0 +1,182 @@
+package adb
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SettingsNamespace is a table of the Android settings provider.
+type SettingsNamespace string
+
+const (
+	// SettingsSystem holds user preferences such as screen timeout.
+	SettingsSystem SettingsNamespace = "system"
+	// SettingsSecure holds preferences apps can read but not write.
+	SettingsSecure SettingsNamespace = "secure"
+	// SettingsGlobal holds device-wide preferences such as animation scales.
+	SettingsGlobal SettingsNamespace = "global"
+)
+
+// Settings reads and writes one namespace of the settings provider through
+// the on-device `settings` tool. Obtain one from [Device.Settings].
+type Settings struct {
+	device    Device
+	namespace SettingsNamespace
+}
+
+// Settings returns a handle to the given settings namespace. No I/O is
+// performed.
+func (d Device) Settings(namespace SettingsNamespace) Settings {
+	return Settings{device: d, namespace: namespace}
+}
+
+// Get returns a setting's value, equivalent to
+// `adb shell settings get <namespace> <key>`. It returns "" for unset keys.
+func (s Settings) Get(ctx context.Context, key string) (string, error) {
+	value, _, err := s.lookup(ctx, key)
+	return value, err
+}
+
+// lookup is Get that also reports whether the key is set; `settings get`
+// prints "null" for unset keys.
+func (s Settings) lookup(ctx context.Context, key string) (string, bool, error) {
+	res, err := s.device.runChecked(ctx, "shell", "settings", "get", string(s.namespace), shellQuote(key))
+	if err != nil {
+		return "", false, err
+	}
+	value := strings.TrimSuffix(strings.TrimSuffix(res.StdoutString(), "\n"), "\r")
+	if value == "null" {
+		return "", false, nil
+	}
+	return value, true, nil
+}
+
+// Put sets a setting, equivalent to
+// `adb shell settings put <namespace> <key> <value>`.
+func (s Settings) Put(ctx context.Context, key, value string) error {
+	return s.device.execChecked(ctx, "shell", "settings", "put", string(s.namespace), shellQuote(key), shellQuote(value))
+}
+
+// Delete removes a setting, equivalent to
+// `adb shell settings delete <namespace> <key>`.
+func (s Settings) Delete(ctx context.Context, key string) error {
+	return s.device.execChecked(ctx, "shell", "settings", "delete", string(s.namespace), shellQuote(key))
+}
+
+// List returns every setting in the namespace, equivalent to
+// `adb shell settings list <namespace>`.
+func (s Settings) List(ctx context.Context) (map[string]string, error) {
+	res, err := s.device.runChecked(ctx, "shell", "settings", "list", string(s.namespace))
+	if err != nil {
+		return nil, err
+	}
+	settings := map[string]string{}
+	for line := range strings.SplitSeq(res.StdoutString(), "\n") {
+		line = strings.TrimSuffix(line, "\r")
+		if key, value, ok := strings.Cut(line, "="); ok && key != "" {
+			settings[key] = value
+		}
+	}
+	return settings, nil
+}
+
+// Setting is a single settings value for [Device.OverrideSettings].
+type Setting struct {
+	Namespace SettingsNamespace
+	Key       string
+	Value     string
+}
+
+// DisableAnimations returns settings that turn off window, transition, and
+// animator animations, which makes UI automation faster and more
+// deterministic.
+func DisableAnimations() []Setting {
+	return []Setting{
+		{Namespace: SettingsGlobal, Key: "window_animation_scale", Value: "0"},
+		{Namespace: SettingsGlobal, Key: "transition_animation_scale", Value: "0"},
+		{Namespace: SettingsGlobal, Key: "animator_duration_scale", Value: "0"},
+	}
+}
+
+// StayAwake returns a setting that keeps the screen on while the device is
+// plugged into any power source.
+func StayAwake() Setting {
+	// A bitmask of BatteryManager.BATTERY_PLUGGED_AC|USB|WIRELESS.
+	return Setting{Namespace: SettingsGlobal, Key: "stay_on_while_plugged_in", Value: "7"}
+}
+
+// ScreenTimeout returns a setting that turns the screen off after d of
+// inactivity.
+func ScreenTimeout(d time.Duration) Setting {
+	return Setting{Namespace: SettingsSystem, Key: "screen_off_timeout", Value: strconv.FormatInt(d.Milliseconds(), 10)}
+}
+
+// SettingsOverride is a set of applied settings overrides. Call
+// [SettingsOverride.Restore] (or Close) to put the original values back.
+type SettingsOverride struct {
+	device Device
+	saved  []savedSetting
+}
+
+type savedSetting struct {
+	Setting
+	wasSet bool
+}
+
+// OverrideSettings records the current value of each setting and then
+// applies the new values, so a test can run with known settings and put the
+// device back afterward:
+//
+//	o, err := d.OverrideSettings(ctx, append(adb.DisableAnimations(), adb.StayAwake())...)
+//	if err != nil {
+//		return err
+//	}
+//	defer o.Close()
+//
+// If applying an override fails, those already applied are restored before
+// the error is returned.
+func (d Device) OverrideSettings(ctx context.Context, settings ...Setting) (*SettingsOverride, error) {
+	o := &SettingsOverride{device: d}
+	for _, s := range settings {
+		ns := d.Settings(s.Namespace)
+		old, wasSet, err := ns.lookup(ctx, s.Key)
+		if err == nil {
+			o.saved = append(o.saved, savedSetting{
+				Setting: Setting{Namespace: s.Namespace, Key: s.Key, Value: old},
+				wasSet:  wasSet,
+			})
+			err = ns.Put(ctx, s.Key, s.Value)
+		}
+		if err != nil {
+			return nil, errors.Join(err, o.Restore(ctx))
+		}
+	}
+	return o, nil
+}
+
+// Restore puts back the values the settings had before they were
+// overridden, deleting those that were unset, in reverse order. Later calls
+// do nothing. It attempts every setting and returns the errors joined.
+func (o *SettingsOverride) Restore(ctx context.Context) error {
+	var errs []error
+	for i := len(o.saved) - 1; i >= 0; i-- {
+		s := o.saved[i]
+		ns := o.device.Settings(s.Namespace)
+		if s.wasSet {
+			errs = append(errs, ns.Put(ctx, s.Key, s.Value))
+		} else {
+			errs = append(errs, ns.Delete(ctx, s.Key))
+		}
+	}
+	o.saved = nil
+	return errors.Join(errs...)
+}
+
+// Close restores the original settings; it is Restore with a background
+// context, for use with defer.
+func (o *SettingsOverride) Close() error {
+	return o.Restore(context.Background())
+}
@@ -0,0 +1,105 @@
+package adb
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestSettings(t *testing.T) {
+	tests := []struct {
+		name string
+		call func(Settings) error
+		want []string
+	}{
+		{"put", func(s Settings) error {
+			return s.Put(context.Background(), "screen_off_timeout", "60000")
+		}, []string{"put", "system", "screen_off_timeout", "60000"}},
+		{"put quoted", func(s Settings) error {
+			return s.Put(context.Background(), "device_name", "Test Phone")
+		}, []string{"put", "system", "device_name", "'Test Phone'"}},
+		{"delete", func(s Settings) error {
+			return s.Delete(context.Background(), "screen_off_timeout")
+		}, []string{"delete", "system", "screen_off_timeout"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, argsFile := fakeADB(t, "", "", 0)
+			if err := tt.call(c.Device("S").Settings(SettingsSystem)); err != nil {
+				t.Fatalf("%s error = %v", tt.name, err)
+			}
+			want := append([]string{"-s", "S", "shell", "settings"}, tt.want...)
+			if got := readArgs(t, argsFile); !reflect.DeepEqual(got, want) {
+				t.Fatalf("args = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestSettings_Get(t *testing.T) {
+	c, argsFile := fakeADB(t, "1.0\n", "", 0)
+	s := c.Device("S").Settings(SettingsGlobal)
+	got, err := s.Get(context.Background(), "window_animation_scale")
+	if err != nil || got != "1.0" {
+		t.Fatalf("Get() = %q, %v; want 1.0", got, err)
+	}
+	want := []string{"-s", "S", "shell", "settings", "get", "global", "window_animation_scale"}
+	if args := readArgs(t, argsFile); !reflect.DeepEqual(args, want) {
+		t.Fatalf("Get() args = %v, want %v", args, want)
+	}
+
+	c, _ = fakeADB(t, "null\n", "", 0)
+	if got, err := c.Device("S").Settings(SettingsGlobal).Get(context.Background(), "missing"); err != nil || got != "" {
+		t.Fatalf("Get(unset) = %q, %v; want empty", got, err)
+	}
+}
+
+func TestSettings_List(t *testing.T) {
+	c, _ := fakeADB(t, "adb_enabled=1\ndevice_name=Test Phone\nempty=\nhttp_proxy=host:8080=x\n", "", 0)
+	got, err := c.Device("S").Settings(SettingsGlobal).List(context.Background())
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	want := map[string]string{"adb_enabled": "1", "device_name": "Test Phone", "empty": "", "http_proxy": "host:8080=x"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("List() = %v, want %v", got, want)
+	}
+}
+
+func TestOverrideSettings(t *testing.T) {
+	c, calls := fakeADBSeq(t,
+		fakeResponse{stdout: "1.0\n"},  // get window_animation_scale
+		fakeResponse{},                 // put
+		fakeResponse{stdout: "null\n"}, // get screen_off_timeout
+		fakeResponse{},                 // put
+		fakeResponse{},                 // restore: delete screen_off_timeout
+		fakeResponse{},                 // restore: put window_animation_scale
+	)
+	o, err := c.Device("S").OverrideSettings(context.Background(),
+		DisableAnimations()[0],
+		ScreenTimeout(10*time.Minute),
+	)
+	if err != nil {
+		t.Fatalf("OverrideSettings() error = %v", err)
+	}
+	if err := o.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	if err := o.Close(); err != nil {
+		t.Fatalf("second Close() error = %v", err)
+	}
+	settings := []string{"-s", "S", "shell", "settings"}
+	cmd := func(args ...string) []string { return append(settings[:4:4], args...) }
+	want := [][]string{
+		cmd("get", "global", "window_animation_scale"),
+		cmd("put", "global", "window_animation_scale", "0"),
+		cmd("get", "system", "screen_off_timeout"),
+		cmd("put", "system", "screen_off_timeout", "600000"),
+		cmd("delete", "system", "screen_off_timeout"),
+		cmd("put", "global", "window_animation_scale", "1.0"),
+	}
+	if got := calls(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("OverrideSettings() calls = %v, want %v", got, want)
+	}
+}
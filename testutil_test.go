@@ -63,3 +63,57 @@ func readArgs(t *testing.T, path string) []string {
 func fakeDevice(c *Client, serial string, transport Transport) Device {
 	return Device{client: c, serial: serial, transport: transport}
 }
+
+// fakeResponse is one scripted reply of a fakeADBSeq stub.
+type fakeResponse struct {
+	stdout, stderr string
+	code           int
+}
+
+// fakeADBSeq writes a stub adb script that replies to its Nth invocation with
+// responses[N], repeating the last response once the script is exhausted. It
+// returns a Client pointed at the stub and a function reporting the args of
+// every invocation so far, in order.
+func fakeADBSeq(t *testing.T, responses ...fakeResponse) (*Client, func() [][]string) {
+	t.Helper()
+
+	dir := t.TempDir()
+	for i, r := range responses {
+		for name, data := range map[string]string{"stdout": r.stdout, "stderr": r.stderr, "code": strconv.Itoa(r.code)} {
+			if err := os.WriteFile(filepath.Join(dir, name+"."+strconv.Itoa(i)), []byte(data), 0o600); err != nil {
+				t.Fatalf("write fake response: %v", err)
+			}
+		}
+	}
+	scriptPath := filepath.Join(dir, "adb")
+	script := `#!/bin/sh
+dir='` + dir + `'
+n=$(cat "$dir/count" 2>/dev/null || echo 0)
+echo $((n + 1)) > "$dir/count"
+printf '%s\0' "$@" > "$dir/args.$n"
+i=$n
+[ "$i" -lt ` + strconv.Itoa(len(responses)) + ` ] || i=` + strconv.Itoa(len(responses)-1) + `
+cat "$dir/stdout.$i"
+cat "$dir/stderr.$i" >&2
+exit "$(cat "$dir/code.$i")"
+`
+	if err := os.WriteFile(scriptPath, []byte(script), 0o755); err != nil {
+		t.Fatalf("write fake adb: %v", err)
+	}
+	client, err := New(WithBinary(scriptPath))
+	if err != nil {
+		t.Fatalf("New(): %v", err)
+	}
+	calls := func() [][]string {
+		t.Helper()
+		var out [][]string
+		for i := 0; ; i++ {
+			path := filepath.Join(dir, "args."+strconv.Itoa(i))
+			if _, err := os.Stat(path); err != nil {
+				return out
+			}
+			out = append(out, readArgs(t, path))
+		}
+	}
+	return client, calls
+}
@@ -0,0 +1,97 @@
+package adb
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"strings"
+)
+
+// defaultTextChunk is the number of characters [Device.TypeText] sends per
+// `input text` call when [TextOptions.ChunkSize] is unset. Very long
+// arguments are slow to inject and may be truncated by older releases.
+const defaultTextChunk = 100
+
+// UnicodeFallback selects how [Device.TypeText] enters text that
+// `input text` cannot type: anything other than printable ASCII, tabs, and
+// newlines.
+type UnicodeFallback int
+
+const (
+	// UnicodeNone rejects such text with [ErrUntypeableText].
+	UnicodeNone UnicodeFallback = iota
+	// UnicodeADBKeyboard sends the text to the ADBKeyBoard input method
+	// (https://github.com/senzhk/ADBKeyBoard) with an ADB_INPUT_B64
+	// broadcast. ADBKeyBoard must be installed and selected as the current
+	// input method; otherwise the broadcast is silently ignored.
+	UnicodeADBKeyboard
+	// UnicodeClipboard puts the text on the clipboard with
+	// [Device.SetClipboard] and pastes it into the focused field. It
+	// replaces the clipboard contents.
+	UnicodeClipboard
+)
+
+// TextOptions configures [Device.TypeText].
+type TextOptions struct {
+	// ChunkSize is the maximum number of characters sent per `input text`
+	// call. Zero uses a default of 100.
+	ChunkSize int
+	// Fallback selects how to enter text `input text` cannot type.
+	Fallback UnicodeFallback
+}
+
+// TypeText types text into the focused field. Text is escaped for both the
+// device shell and `input text` (which reads "%s" as a space), so quotes,
+// "&", "%", and other special characters are typed literally, and long text
+// is sent in chunks. Text containing characters `input text` cannot type is
+// entered through opts.Fallback instead.
+func (d Device) TypeText(ctx context.Context, text string, opts TextOptions) error {
+	if strings.IndexFunc(text, func(r rune) bool { return (r < ' ' || r > '~') && r != '\t' && r != '\n' }) >= 0 {
+		switch opts.Fallback {
+		case UnicodeADBKeyboard:
+			msg := base64.StdEncoding.EncodeToString([]byte(text))
+			return d.SendBroadcast(ctx, Intent{Action: "ADB_INPUT_B64"}.WithString("msg", msg))
+		case UnicodeClipboard:
+			return d.pasteText(ctx, text)
+		default:
+			return fmt.Errorf("%w: %q", ErrUntypeableText, text)
+		}
+	}
+	size := opts.ChunkSize
+	if size <= 0 {
+		size = defaultTextChunk
+	}
+	for _, chunk := range textChunks(text, size) {
+		if err := d.input(ctx, "text", shellQuote(chunk)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// textChunks splits ASCII text into `input text` arguments of at most size
+// characters, with spaces encoded as "%s". A literal "%s" in the text cannot
+// be expressed within one argument, so the text is also split between the
+// "%" and the "s".
+func textChunks(text string, size int) []string {
+	var chunks []string
+	var cur strings.Builder
+	n := 0
+	for i := 0; i < len(text); i++ {
+		if n == size || (text[i] == 's' && i > 0 && text[i-1] == '%' && n > 0) {
+			chunks = append(chunks, cur.String())
+			cur.Reset()
+			n = 0
+		}
+		if text[i] == ' ' {
+			cur.WriteString("%s")
+		} else {
+			cur.WriteByte(text[i])
+		}
+		n++
+	}
+	if n > 0 {
+		chunks = append(chunks, cur.String())
+	}
+	return chunks
+}
@@ -0,0 +1,60 @@
+package adb
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestTextChunks(t *testing.T) {
+	tests := []struct {
+		text string
+		size int
+		want []string
+	}{
+		{"hello world", 100, []string{"hello%sworld"}},
+		{"50% off", 100, []string{"50%%soff"}},
+		{"100%sure", 100, []string{"100%", "sure"}},
+		{"abcdefg", 3, []string{"abc", "def", "g"}},
+		{"a b c", 2, []string{"a%s", "b%s", "c"}},
+		{"", 100, nil},
+	}
+	for _, tt := range tests {
+		if got := textChunks(tt.text, tt.size); !reflect.DeepEqual(got, tt.want) {
+			t.Fatalf("textChunks(%q, %d) = %q, want %q", tt.text, tt.size, got, tt.want)
+		}
+	}
+}
+
+func TestTypeText_Escaping(t *testing.T) {
+	c, calls := fakeADBSeq(t, fakeResponse{})
+	if err := c.Device("S").TypeText(context.Background(), `p&ss'w%sd "x"`, TextOptions{}); err != nil {
+		t.Fatalf("TypeText() error = %v", err)
+	}
+	want := [][]string{
+		{"-s", "S", "shell", "input", "text", `'p&ss'\''w%'`},
+		{"-s", "S", "shell", "input", "text", `'sd%s"x"'`},
+	}
+	if got := calls(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("TypeText() calls = %q, want %q", got, want)
+	}
+}
+
+func TestTypeText_Unicode(t *testing.T) {
+	c, _ := fakeADB(t, "", "", 0)
+	if err := c.Device("S").TypeText(context.Background(), "héllo", TextOptions{}); !errors.Is(err, ErrUntypeableText) {
+		t.Fatalf("TypeText() error = %v, want ErrUntypeableText", err)
+	}
+
+	c, argsFile := fakeADB(t, "Broadcast completed: result=0\n", "", 0)
+	if err := c.Device("S").TypeText(context.Background(), "héllo 👋", TextOptions{Fallback: UnicodeADBKeyboard}); err != nil {
+		t.Fatalf("TypeText() error = %v", err)
+	}
+	msg := base64.StdEncoding.EncodeToString([]byte("héllo 👋"))
+	want := []string{"-s", "S", "shell", "am", "broadcast", "-a", "ADB_INPUT_B64", "--es", "msg", msg}
+	if got := readArgs(t, argsFile); !reflect.DeepEqual(got, want) {
+		t.Fatalf("TypeText() args = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,548 @@
+package adb
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"iter"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Rect is an axis-aligned rectangle in screen pixels. Right and Bottom are
+// exclusive, matching the bounds uiautomator reports.
+type Rect struct {
+	Left, Top, Right, Bottom int
+}
+
+// Center returns the point in the middle of the rectangle.
+func (r Rect) Center() (x, y int) {
+	return (r.Left + r.Right) / 2, (r.Top + r.Bottom) / 2
+}
+
+// Empty reports whether the rectangle has no area.
+func (r Rect) Empty() bool { return r.Right <= r.Left || r.Bottom <= r.Top }
+
+// UINode is a single view in a [UIHierarchy], as reported by
+// `uiautomator dump`.
+type UINode struct {
+	// Index is the node's position among its siblings.
+	Index         int
+	Text          string
+	ResourceID    string
+	Class         string
+	Package       string
+	ContentDesc   string
+	Checkable     bool
+	Checked       bool
+	Clickable     bool
+	Enabled       bool
+	Focusable     bool
+	Focused       bool
+	Scrollable    bool
+	LongClickable bool
+	Password      bool
+	Selected      bool
+	Bounds        Rect
+	Children      []*UINode
+
+	parent *UINode
+}
+
+// Parent returns the node's parent, or nil for a top-level node.
+func (n *UINode) Parent() *UINode { return n.parent }
+
+// All yields the node and all of its descendants in depth-first order.
+func (n *UINode) All() iter.Seq[*UINode] {
+	return func(yield func(*UINode) bool) {
+		n.walk(yield)
+	}
+}
+
+func (n *UINode) walk(yield func(*UINode) bool) bool {
+	if !yield(n) {
+		return false
+	}
+	for _, c := range n.Children {
+		if !c.walk(yield) {
+			return false
+		}
+	}
+	return true
+}
+
+// Find returns the first node in the subtree rooted at n (including n) that
+// matches sel.
+func (n *UINode) Find(sel Selector) (*UINode, bool) {
+	for node := range n.All() {
+		if sel.Match(node) {
+			return node, true
+		}
+	}
+	return nil, false
+}
+
+// FindAll returns every node in the subtree rooted at n (including n) that
+// matches sel, in depth-first order.
+func (n *UINode) FindAll(sel Selector) []*UINode {
+	var out []*UINode
+	for node := range n.All() {
+		if sel.Match(node) {
+			out = append(out, node)
+		}
+	}
+	return out
+}
+
+// UIHierarchy is a snapshot of the on-screen view tree, obtained from
+// [Device.DumpUI].
+type UIHierarchy struct {
+	// Rotation is the display rotation (0-3) at the time of the dump.
+	Rotation int
+	// Nodes are the top-level windows' root views.
+	Nodes []*UINode
+
+	raw []byte
+}
+
+// All yields every node in the hierarchy in depth-first order.
+func (h *UIHierarchy) All() iter.Seq[*UINode] {
+	return func(yield func(*UINode) bool) {
+		for _, n := range h.Nodes {
+			if !n.walk(yield) {
+				return
+			}
+		}
+	}
+}
+
+// Find returns the first node in the hierarchy that matches sel.
+func (h *UIHierarchy) Find(sel Selector) (*UINode, bool) {
+	for node := range h.All() {
+		if sel.Match(node) {
+			return node, true
+		}
+	}
+	return nil, false
+}
+
+// FindAll returns every node in the hierarchy that matches sel, in
+// depth-first order.
+func (h *UIHierarchy) FindAll(sel Selector) []*UINode {
+	var out []*UINode
+	for node := range h.All() {
+		if sel.Match(node) {
+			out = append(out, node)
+		}
+	}
+	return out
+}
+
+// XML returns the raw XML the hierarchy was parsed from.
+func (h *UIHierarchy) XML() []byte { return h.raw }
+
+// DumpUI captures the current UI hierarchy, equivalent to
+// `adb exec-out uiautomator dump /dev/tty`. It returns
+// [ErrUIHierarchyParseFail] when uiautomator does not produce a hierarchy (for
+// example while the screen is animating or locked).
+func (d Device) DumpUI(ctx context.Context) (*UIHierarchy, error) {
+	res, err := d.client.run(ctx, "-s", d.serial, "exec-out", "uiautomator", "dump", "/dev/tty")
+	if err != nil {
+		return nil, err
+	}
+	return parseUIHierarchy(res.Stdout)
+}
+
+// TapElement dumps the UI hierarchy and taps the centre of the first node
+// matching sel. It returns [ErrElementNotFound] when no node matches.
+func (d Device) TapElement(ctx context.Context, sel Selector) error {
+	h, err := d.DumpUI(ctx)
+	if err != nil {
+		return err
+	}
+	node, ok := h.Find(sel)
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrElementNotFound, sel)
+	}
+	x, y := node.Bounds.Center()
+	return d.Tap(ctx, x, y)
+}
+
+// xmlNode mirrors the <node> element written by uiautomator.
+type xmlNode struct {
+	Index         string    `xml:"index,attr"`
+	Text          string    `xml:"text,attr"`
+	ResourceID    string    `xml:"resource-id,attr"`
+	Class         string    `xml:"class,attr"`
+	Package       string    `xml:"package,attr"`
+	ContentDesc   string    `xml:"content-desc,attr"`
+	Checkable     string    `xml:"checkable,attr"`
+	Checked       string    `xml:"checked,attr"`
+	Clickable     string    `xml:"clickable,attr"`
+	Enabled       string    `xml:"enabled,attr"`
+	Focusable     string    `xml:"focusable,attr"`
+	Focused       string    `xml:"focused,attr"`
+	Scrollable    string    `xml:"scrollable,attr"`
+	LongClickable string    `xml:"long-clickable,attr"`
+	Password      string    `xml:"password,attr"`
+	Selected      string    `xml:"selected,attr"`
+	Bounds        string    `xml:"bounds,attr"`
+	Nodes         []xmlNode `xml:"node"`
+}
+
+type xmlHierarchy struct {
+	Rotation string    `xml:"rotation,attr"`
+	Nodes    []xmlNode `xml:"node"`
+}
+
+// parseUIHierarchy extracts the <hierarchy> document from uiautomator output.
+// The dump is surrounded by status text ("UI hierchary dumped to: /dev/tty"),
+// so only the XML between the first <?xml or <hierarchy and the closing tag is
+// decoded.
+func parseUIHierarchy(out []byte) (*UIHierarchy, error) {
+	start := bytes.Index(out, []byte("<?xml"))
+	if start < 0 {
+		start = bytes.Index(out, []byte("<hierarchy"))
+	}
+	const closing = "</hierarchy>"
+	end := bytes.LastIndex(out, []byte(closing))
+	if start < 0 || end < start {
+		return nil, ErrUIHierarchyParseFail
+	}
+	raw := out[start : end+len(closing)]
+
+	var doc xmlHierarchy
+	if err := xml.Unmarshal(raw, &doc); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrUIHierarchyParseFail, err)
+	}
+	rotation, _ := strconv.Atoi(doc.Rotation)
+	h := &UIHierarchy{Rotation: rotation, raw: raw}
+	for _, xn := range doc.Nodes {
+		h.Nodes = append(h.Nodes, xn.toNode(nil))
+	}
+	return h, nil
+}
+
+func (x xmlNode) toNode(parent *UINode) *UINode {
+	index, _ := strconv.Atoi(x.Index)
+	n := &UINode{
+		Index:         index,
+		Text:          x.Text,
+		ResourceID:    x.ResourceID,
+		Class:         x.Class,
+		Package:       x.Package,
+		ContentDesc:   x.ContentDesc,
+		Checkable:     x.Checkable == "true",
+		Checked:       x.Checked == "true",
+		Clickable:     x.Clickable == "true",
+		Enabled:       x.Enabled == "true",
+		Focusable:     x.Focusable == "true",
+		Focused:       x.Focused == "true",
+		Scrollable:    x.Scrollable == "true",
+		LongClickable: x.LongClickable == "true",
+		Password:      x.Password == "true",
+		Selected:      x.Selected == "true",
+		Bounds:        parseBounds(x.Bounds),
+		parent:        parent,
+	}
+	for _, c := range x.Nodes {
+		n.Children = append(n.Children, c.toNode(n))
+	}
+	return n
+}
+
+var reBounds = regexp.MustCompile(`^\[(-?\d+),(-?\d+)\]\[(-?\d+),(-?\d+)\]$`)
+
+// parseBounds parses uiautomator's "[left,top][right,bottom]" notation. A
+// malformed value yields the zero Rect.
+func parseBounds(s string) Rect {
+	m := reBounds.FindStringSubmatch(s)
+	if len(m) != 5 {
+		return Rect{}
+	}
+	l, _ := strconv.Atoi(m[1])
+	t, _ := strconv.Atoi(m[2])
+	r, _ := strconv.Atoi(m[3])
+	b, _ := strconv.Atoi(m[4])
+	return Rect{Left: l, Top: t, Right: r, Bottom: b}
+}
+
+// Selector matches nodes in a [UIHierarchy]. Build selectors with [ByText],
+// [ByResourceID], [ByClass], [ByContentDesc], [CompilePath], and combine them
+// with [And]. The zero Selector matches nothing.
+type Selector struct {
+	desc  string
+	match func(*UINode) bool
+}
+
+// Match reports whether n satisfies the selector.
+func (s Selector) Match(n *UINode) bool {
+	return s.match != nil && n != nil && s.match(n)
+}
+
+// String returns a human-readable description of the selector.
+func (s Selector) String() string { return s.desc }
+
+// ByText matches nodes whose text is exactly text.
+func ByText(text string) Selector {
+	return Selector{
+		desc:  fmt.Sprintf("text=%q", text),
+		match: func(n *UINode) bool { return n.Text == text },
+	}
+}
+
+// ByTextContains matches nodes whose text contains substr.
+func ByTextContains(substr string) Selector {
+	return Selector{
+		desc:  fmt.Sprintf("text~=%q", substr),
+		match: func(n *UINode) bool { return strings.Contains(n.Text, substr) },
+	}
+}
+
+// ByResourceID matches nodes with the given resource id. A bare id ("login")
+// also matches fully qualified ids ("com.example:id/login").
+func ByResourceID(id string) Selector {
+	return Selector{
+		desc:  fmt.Sprintf("resource-id=%q", id),
+		match: func(n *UINode) bool { return resourceIDMatches(n.ResourceID, id) },
+	}
+}
+
+func resourceIDMatches(have, want string) bool {
+	if have == want {
+		return true
+	}
+	if strings.Contains(want, ":id/") {
+		return false
+	}
+	_, name, ok := strings.Cut(have, ":id/")
+	return ok && name == want
+}
+
+// ByClass matches nodes whose class is class. A simple name ("Button") also
+// matches fully qualified names ("android.widget.Button").
+func ByClass(class string) Selector {
+	return Selector{
+		desc:  fmt.Sprintf("class=%q", class),
+		match: func(n *UINode) bool { return classMatches(n.Class, class) },
+	}
+}
+
+func classMatches(have, want string) bool {
+	return have == want || (!strings.Contains(want, ".") && strings.HasSuffix(have, "."+want))
+}
+
+// ByContentDesc matches nodes whose content description is exactly desc.
+func ByContentDesc(desc string) Selector {
+	return Selector{
+		desc:  fmt.Sprintf("content-desc=%q", desc),
+		match: func(n *UINode) bool { return n.ContentDesc == desc },
+	}
+}
+
+// And matches nodes that satisfy every one of sels.
+func And(sels ...Selector) Selector {
+	descs := make([]string, len(sels))
+	for i, s := range sels {
+		descs[i] = s.desc
+	}
+	return Selector{
+		desc: strings.Join(descs, " && "),
+		match: func(n *UINode) bool {
+			for _, s := range sels {
+				if !s.Match(n) {
+					return false
+				}
+			}
+			return len(sels) > 0
+		},
+	}
+}
+
+// pathStep is a single "/"-separated component of a path selector.
+type pathStep struct {
+	class    string // "*" matches any class
+	index    int
+	hasIndex bool
+	attrs    []pathAttr
+}
+
+// pathAttr is an attribute equality predicate of a path step.
+type pathAttr struct {
+	name, val string
+}
+
+var rePathClass = regexp.MustCompile(`^[\w.$*]+`)
+
+// CompilePath parses an XPath-like path into a Selector. A path is a list of
+// class names separated by "/", each optionally followed by predicates:
+//
+//	[2]                 the node's sibling index (uiautomator's index, 0-based)
+//	[@text='OK']        an attribute equality (text, resource-id, class,
+//	                    package, content-desc)
+//
+// A step may carry several predicates, all of which must hold. Quoted values
+// may contain "/" and "]". "*" matches any class, and simple class names
+// match their fully qualified form. A leading "//" (or no leading slash)
+// matches the steps anywhere in the tree; a single leading "/" anchors the
+// first step at a top-level node. For example
+// "//ListView/LinearLayout[1]/TextView[@text='Wi-Fi']".
+func CompilePath(path string) (Selector, error) {
+	anchored := strings.HasPrefix(path, "/") && !strings.HasPrefix(path, "//")
+	trimmed := strings.TrimLeft(path, "/")
+	if trimmed == "" {
+		return Selector{}, fmt.Errorf("invalid UI path %q", path)
+	}
+	var steps []pathStep
+	for _, part := range splitPath(trimmed) {
+		class := rePathClass.FindString(part)
+		preds, ok := splitPredicates(part[len(class):])
+		if class == "" || !ok {
+			return Selector{}, fmt.Errorf("invalid UI path %q: bad step %q", path, part)
+		}
+		step := pathStep{class: class}
+		for _, pred := range preds {
+			pred = strings.TrimSpace(pred)
+			if i, err := strconv.Atoi(pred); err == nil {
+				step.index, step.hasIndex = i, true
+				continue
+			}
+			attr, ok := strings.CutPrefix(pred, "@")
+			name, val, hasVal := strings.Cut(attr, "=")
+			if !ok || !hasVal || len(val) < 2 ||
+				(val[0] != '\'' && val[0] != '"') || val[len(val)-1] != val[0] {
+				return Selector{}, fmt.Errorf("invalid UI path %q: bad predicate %q", path, pred)
+			}
+			step.attrs = append(step.attrs, pathAttr{name: name, val: val[1 : len(val)-1]})
+			if _, known := nodeAttr(&UINode{}, name); !known {
+				return Selector{}, fmt.Errorf("invalid UI path %q: unknown attribute %q", path, name)
+			}
+		}
+		steps = append(steps, step)
+	}
+	return Selector{
+		desc:  "path=" + path,
+		match: func(n *UINode) bool { return matchPath(n, steps, anchored) },
+	}, nil
+}
+
+// splitPath splits a path into steps on the "/" separators that are outside
+// predicates and quoted strings, so attribute values may contain "/".
+func splitPath(path string) []string {
+	var parts []string
+	var quote byte
+	depth, start := 0, 0
+	for i := 0; i < len(path); i++ {
+		switch c := path[i]; {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"':
+			quote = c
+		case c == '[':
+			depth++
+		case c == ']':
+			depth = max(depth-1, 0)
+		case c == '/' && depth == 0:
+			parts = append(parts, path[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, path[start:])
+}
+
+// splitPredicates splits a run of "[...]" predicates into their contents,
+// honouring quoted strings that contain brackets. It reports false if s is
+// anything other than a sequence of well-formed predicates.
+func splitPredicates(s string) ([]string, bool) {
+	var preds []string
+	for s != "" {
+		if s[0] != '[' {
+			return nil, false
+		}
+		var quote byte
+		end := -1
+		for i := 1; i < len(s) && end < 0; i++ {
+			switch c := s[i]; {
+			case quote != 0:
+				if c == quote {
+					quote = 0
+				}
+			case c == '\'' || c == '"':
+				quote = c
+			case c == ']':
+				end = i
+			}
+		}
+		if end < 0 {
+			return nil, false
+		}
+		preds = append(preds, s[1:end])
+		s = s[end+1:]
+	}
+	return preds, true
+}
+
+// MustCompilePath is like [CompilePath] but panics if the path is invalid. It
+// simplifies initialization of package-level selectors.
+func MustCompilePath(path string) Selector {
+	s, err := CompilePath(path)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+// matchPath checks the steps against n and its ancestors, last step first.
+func matchPath(n *UINode, steps []pathStep, anchored bool) bool {
+	cur := n
+	for i := len(steps) - 1; i >= 0; i-- {
+		if cur == nil || !steps[i].matches(cur) {
+			return false
+		}
+		cur = cur.parent
+	}
+	return !anchored || cur == nil
+}
+
+func (s pathStep) matches(n *UINode) bool {
+	if s.class != "*" && !classMatches(n.Class, s.class) {
+		return false
+	}
+	if s.hasIndex && n.Index != s.index {
+		return false
+	}
+	for _, a := range s.attrs {
+		v, _ := nodeAttr(n, a.name)
+		if a.name == "class" {
+			if !classMatches(v, a.val) {
+				return false
+			}
+		} else if v != a.val {
+			return false
+		}
+	}
+	return true
+}
+
+// nodeAttr returns the string attribute of n named by its uiautomator XML
+// name. The boolean is false for unsupported attributes.
+func nodeAttr(n *UINode, name string) (string, bool) {
+	switch name {
+	case "text":
+		return n.Text, true
+	case "resource-id":
+		return n.ResourceID, true
+	case "class":
+		return n.Class, true
+	case "package":
+		return n.Package, true
+	case "content-desc":
+		return n.ContentDesc, true
+	default:
+		return "", false
+	}
+}
@@ -0,0 +1,115 @@
+package adb
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+const uiDump = `<?xml version='1.0' encoding='UTF-8' standalone='yes' ?><hierarchy rotation="0">` +
+	`<node index="0" text="" resource-id="" class="android.widget.FrameLayout" package="com.example" content-desc="" clickable="false" enabled="true" bounds="[0,0][1080,2340]">` +
+	`<node index="0" text="" resource-id="com.example:id/list" class="android.widget.ListView" package="com.example" content-desc="" scrollable="true" bounds="[0,200][1080,2000]">` +
+	`<node index="0" text="Wi-Fi" resource-id="com.example:id/title" class="android.widget.TextView" package="com.example" content-desc="" clickable="true" bounds="[0,200][1080,300]" />` +
+	`<node index="1" text="Bluetooth" resource-id="com.example:id/title" class="android.widget.TextView" package="com.example" content-desc="" clickable="true" bounds="[0,300][1080,400]" />` +
+	`</node>` +
+	`<node index="1" text="OK" resource-id="com.example:id/ok" class="android.widget.Button" package="com.example" content-desc="Confirm" clickable="true" focused="true" bounds="[100,2100][300,2200]" />` +
+	`</node></hierarchy>UI hierchary dumped to: /dev/tty`
+
+func TestParseUIHierarchy(t *testing.T) {
+	h, err := parseUIHierarchy([]byte(uiDump))
+	if err != nil {
+		t.Fatalf("parseUIHierarchy() error = %v", err)
+	}
+	if len(h.Nodes) != 1 || len(h.Nodes[0].Children) != 2 {
+		t.Fatalf("unexpected tree shape: %#v", h.Nodes)
+	}
+	ok := h.Nodes[0].Children[1]
+	want := Rect{Left: 100, Top: 2100, Right: 300, Bottom: 2200}
+	if ok.Text != "OK" || ok.Bounds != want || !ok.Clickable || !ok.Focused || ok.ContentDesc != "Confirm" {
+		t.Fatalf("OK node = %#v", ok)
+	}
+	if ok.Parent() != h.Nodes[0] {
+		t.Fatal("OK node parent not linked")
+	}
+	var count int
+	for range h.All() {
+		count++
+	}
+	if count != 5 {
+		t.Fatalf("All() visited %d nodes, want 5", count)
+	}
+}
+
+func TestParseUIHierarchy_NoDump(t *testing.T) {
+	_, err := parseUIHierarchy([]byte("ERROR: could not get idle state.\n"))
+	if !errors.Is(err, ErrUIHierarchyParseFail) {
+		t.Fatalf("parseUIHierarchy() error = %v, want ErrUIHierarchyParseFail", err)
+	}
+}
+
+func TestSelectors(t *testing.T) {
+	h, err := parseUIHierarchy([]byte(uiDump))
+	if err != nil {
+		t.Fatalf("parseUIHierarchy() error = %v", err)
+	}
+	tests := []struct {
+		name string
+		sel  Selector
+		want []string
+	}{
+		{"text", ByText("Bluetooth"), []string{"Bluetooth"}},
+		{"text contains", ByTextContains("-F"), []string{"Wi-Fi"}},
+		{"bare resource id", ByResourceID("title"), []string{"Wi-Fi", "Bluetooth"}},
+		{"qualified resource id", ByResourceID("com.example:id/ok"), []string{"OK"}},
+		{"simple class", ByClass("Button"), []string{"OK"}},
+		{"content desc", ByContentDesc("Confirm"), []string{"OK"}},
+		{"and", And(ByResourceID("title"), ByText("Wi-Fi")), []string{"Wi-Fi"}},
+		{"path index", MustCompilePath("//ListView/TextView[1]"), []string{"Bluetooth"}},
+		{"path attribute", MustCompilePath("ListView/*[@text='Wi-Fi']"), []string{"Wi-Fi"}},
+		{"anchored path", MustCompilePath("/FrameLayout/Button"), []string{"OK"}},
+		{"path attributes", MustCompilePath("//TextView[@text='Bluetooth'][@resource-id='com.example:id/title']"), []string{"Bluetooth"}},
+		{"path attributes all required", MustCompilePath("//TextView[@text='nope'][@resource-id='com.example:id/title']"), nil},
+		{"path attribute with slash", MustCompilePath("//FrameLayout/*[@resource-id='com.example:id/ok']"), []string{"OK"}},
+		{"anchored path miss", MustCompilePath("/ListView/TextView"), nil},
+		{"zero selector", Selector{}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, n := range h.FindAll(tt.sel) {
+				got = append(got, n.Text)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("FindAll(%s) = %v, want %v", tt.sel, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompilePath_Invalid(t *testing.T) {
+	for _, path := range []string{"", "/", "Button[", "Button[@text=OK]", "Button[@bogus='x']", "Button[@text='a/b'", "Button[1]x", "a b"} {
+		if _, err := CompilePath(path); err == nil {
+			t.Fatalf("CompilePath(%q) expected error", path)
+		}
+	}
+}
+
+func TestTapElement(t *testing.T) {
+	c, argsFile := fakeADB(t, uiDump, "", 0)
+	if err := c.Device("S").TapElement(context.Background(), ByText("OK")); err != nil {
+		t.Fatalf("TapElement() error = %v", err)
+	}
+	want := []string{"-s", "S", "shell", "input", "tap", "200", "2150"}
+	if got := readArgs(t, argsFile); !reflect.DeepEqual(got, want) {
+		t.Fatalf("TapElement() args = %v, want %v", got, want)
+	}
+}
+
+func TestTapElement_NotFound(t *testing.T) {
+	c, _ := fakeADB(t, uiDump, "", 0)
+	err := c.Device("S").TapElement(context.Background(), ByText("Cancel"))
+	if !errors.Is(err, ErrElementNotFound) {
+		t.Fatalf("TapElement() error = %v, want ErrElementNotFound", err)
+	}
+}
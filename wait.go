@@ -0,0 +1,263 @@
+package adb
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"time"
+)
+
+// Polling backoff used by the WaitFor* helpers: the first re-check happens
+// after pollInitial and the interval doubles up to pollMax.
+const (
+	pollInitial = 100 * time.Millisecond
+	pollMax     = 2 * time.Second
+)
+
+// WaitError is returned when a WaitFor* helper gives up because its context
+// expired before the condition held. It records the last observed device
+// state to help diagnose why the condition never became true, and unwraps to
+// the context error so callers can match [context.DeadlineExceeded].
+type WaitError struct {
+	// Condition describes what was being waited for.
+	Condition string
+	// Hierarchy is the last UI hierarchy observed, if the condition inspects
+	// the UI. It may be nil if no dump succeeded.
+	Hierarchy *UIHierarchy
+	// Activity is the last foreground activity observed, if the condition
+	// inspects it.
+	Activity string
+	// Err is the context error that ended the wait.
+	Err error
+}
+
+func (e *WaitError) Error() string {
+	msg := "timed out waiting for " + e.Condition
+	if e.Activity != "" {
+		msg += " (foreground activity " + e.Activity + ")"
+	}
+	return msg + ": " + e.Err.Error()
+}
+
+// Unwrap returns the context error that ended the wait.
+func (e *WaitError) Unwrap() error { return e.Err }
+
+// poll calls check until it reports done, returns an error, or ctx expires.
+// Between attempts it sleeps with exponential backoff. When ctx expires the
+// returned error is ctx.Err(); check errors observed after expiry are assumed
+// to be caused by it and are discarded.
+func poll(ctx context.Context, check func(context.Context) (bool, error)) error {
+	interval := pollInitial
+	for {
+		done, err := check(ctx)
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			if done && err == nil {
+				return nil
+			}
+			return ctxErr
+		}
+		if err != nil || done {
+			return err
+		}
+		timer := time.NewTimer(interval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+		interval = min(interval*2, pollMax)
+	}
+}
+
+// dumpUIRetry is DumpUI that reports a missing hierarchy (uiautomator can fail
+// transiently while the screen animates) as a nil hierarchy rather than an
+// error, so polling can retry.
+func (d Device) dumpUIRetry(ctx context.Context) (*UIHierarchy, error) {
+	h, err := d.DumpUI(ctx)
+	if errors.Is(err, ErrUIHierarchyParseFail) {
+		return nil, nil
+	}
+	return h, err
+}
+
+// WaitForElement polls the UI hierarchy until a node matching sel appears and
+// returns it. Bound the wait with a context deadline; on expiry it returns a
+// *[WaitError] carrying the last hierarchy observed.
+func (d Device) WaitForElement(ctx context.Context, sel Selector) (*UINode, error) {
+	var last *UIHierarchy
+	var found *UINode
+	err := poll(ctx, func(ctx context.Context) (bool, error) {
+		h, err := d.dumpUIRetry(ctx)
+		if err != nil || h == nil {
+			return false, err
+		}
+		last = h
+		node, ok := h.Find(sel)
+		found = node
+		return ok, nil
+	})
+	if err != nil {
+		return nil, waitErr(ctx, err, &WaitError{Condition: "element " + sel.String(), Hierarchy: last})
+	}
+	return found, nil
+}
+
+// WaitForGone polls the UI hierarchy until no node matches sel. On context
+// expiry it returns a *[WaitError] carrying the last hierarchy observed.
+func (d Device) WaitForGone(ctx context.Context, sel Selector) error {
+	var last *UIHierarchy
+	err := poll(ctx, func(ctx context.Context) (bool, error) {
+		h, err := d.dumpUIRetry(ctx)
+		if err != nil || h == nil {
+			return false, err
+		}
+		last = h
+		_, ok := h.Find(sel)
+		return !ok, nil
+	})
+	return waitErr(ctx, err, &WaitError{Condition: "element " + sel.String() + " to disappear", Hierarchy: last})
+}
+
+// WaitForIdle polls the UI hierarchy until two consecutive dumps are
+// identical, indicating that the screen has settled. On context expiry it
+// returns a *[WaitError] carrying the last hierarchy observed.
+func (d Device) WaitForIdle(ctx context.Context) error {
+	var last *UIHierarchy
+	err := poll(ctx, func(ctx context.Context) (bool, error) {
+		h, err := d.dumpUIRetry(ctx)
+		if err != nil || h == nil {
+			return false, err
+		}
+		prev := last
+		last = h
+		return prev != nil && bytes.Equal(prev.raw, h.raw), nil
+	})
+	return waitErr(ctx, err, &WaitError{Condition: "UI to become idle", Hierarchy: last})
+}
+
+// WaitForActivity polls until component (for example
+// "com.example/.MainActivity", or just a package name) is the resumed
+// foreground activity. On context expiry it returns a *[WaitError] carrying
+// the last foreground activity observed.
+func (d Device) WaitForActivity(ctx context.Context, component string) error {
+	var last string
+	err := poll(ctx, func(ctx context.Context) (bool, error) {
+		act, err := d.ForegroundActivity(ctx)
+		if err != nil {
+			return false, err
+		}
+		last = act
+		return sameComponent(act, component), nil
+	})
+	return waitErr(ctx, err, &WaitError{Condition: "activity " + component, Activity: last})
+}
+
+// waitErr converts a poll error caused by context expiry into we, and passes
+// any other error (or nil) through unchanged.
+func waitErr(ctx context.Context, err error, we *WaitError) error {
+	if err == nil {
+		return nil
+	}
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		we.Err = ctxErr
+		return we
+	}
+	return err
+}
+
+// sameComponent reports whether the component have refers to want. Short
+// class names ("pkg/.Main") are expanded before comparison, and a want with no
+// "/" matches any activity in that package.
+func sameComponent(have, want string) bool {
+	if have == "" || want == "" {
+		return false
+	}
+	if !strings.Contains(want, "/") {
+		pkg, _, _ := strings.Cut(have, "/")
+		return pkg == want
+	}
+	return expandComponent(have) == expandComponent(want)
+}
+
+func expandComponent(c string) string {
+	pkg, cls, ok := strings.Cut(c, "/")
+	if ok && strings.HasPrefix(cls, ".") {
+		return pkg + "/" + pkg + cls
+	}
+	return c
+}
+
+// WaitState is a device state that [Device.WaitFor] can wait for.
+type WaitState string
+
+const (
+	// WaitDevice waits for the device to be online and authorized.
+	WaitDevice WaitState = "device"
+	// WaitRecovery waits for the device to be in recovery.
+	WaitRecovery WaitState = "recovery"
+	// WaitRescue waits for the device to be in rescue mode (Android 10+).
+	WaitRescue WaitState = "rescue"
+	// WaitSideload waits for the device to be in sideload mode.
+	WaitSideload WaitState = "sideload"
+	// WaitBootloader waits for the device to be in the bootloader.
+	WaitBootloader WaitState = "bootloader"
+	// WaitDisconnect waits for the device to go away.
+	WaitDisconnect WaitState = "disconnect"
+)
+
+// WaitFor blocks until the device reaches state over the given transport, or
+// ctx is cancelled. It is equivalent to `adb wait-for[-TRANSPORT]-STATE`:
+// [USB] waits on USB ("usb"), [Network] on TCP/IP ("local"), and
+// [UnknownTransport] on either.
+func (d Device) WaitFor(ctx context.Context, transport Transport, state WaitState) error {
+	return d.exec(ctx, waitForArg(transport, state))
+}
+
+func waitForArg(transport Transport, state WaitState) string {
+	switch transport {
+	case USB:
+		return "wait-for-usb-" + string(state)
+	case Network:
+		return "wait-for-local-" + string(state)
+	default:
+		return "wait-for-" + string(state)
+	}
+}
+
+// WaitForAny blocks until a device that was not already authorized when it
+// was called comes online and authorizes debugging, and returns it. Devices
+// attached beforehand are ignored, so it suits stations that wait for
+// whichever device is plugged in next. Bound the wait with a context
+// deadline.
+func (c *Client) WaitForAny(ctx context.Context) (Device, error) {
+	devs, err := c.Devices(ctx)
+	if err != nil {
+		return Device{}, err
+	}
+	known := map[string]bool{}
+	for _, d := range devs {
+		if d.authorized {
+			known[d.serial] = true
+		}
+	}
+	var found Device
+	err = poll(ctx, func(ctx context.Context) (bool, error) {
+		devs, err := c.Devices(ctx)
+		if err != nil {
+			return false, err
+		}
+		for _, d := range devs {
+			if d.authorized && !known[d.serial] {
+				found = d
+				return true, nil
+			}
+		}
+		return false, nil
+	})
+	if err != nil {
+		return Device{}, err
+	}
+	return found, nil
+}
@@ -0,0 +1,164 @@
+package adb
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestWaitForElement_AppearsAfterRetries(t *testing.T) {
+	c, calls := fakeADBSeq(t,
+		fakeResponse{stdout: "ERROR: could not get idle state.\n"},
+		fakeResponse{stdout: `<hierarchy rotation="0"><node index="0" text="Loading" bounds="[0,0][10,10]" /></hierarchy>`},
+		fakeResponse{stdout: uiDump},
+	)
+	node, err := c.Device("S").WaitForElement(context.Background(), ByText("OK"))
+	if err != nil {
+		t.Fatalf("WaitForElement() error = %v", err)
+	}
+	if node.ResourceID != "com.example:id/ok" {
+		t.Fatalf("WaitForElement() node = %#v", node)
+	}
+	if n := len(calls()); n != 3 {
+		t.Fatalf("WaitForElement() made %d dumps, want 3", n)
+	}
+}
+
+func TestWaitForElement_TimeoutCarriesLastState(t *testing.T) {
+	c, _ := fakeADBSeq(t, fakeResponse{stdout: uiDump})
+	ctx, cancel := context.WithTimeout(context.Background(), 300*time.Millisecond)
+	defer cancel()
+	_, err := c.Device("S").WaitForElement(ctx, ByText("Cancel"))
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("WaitForElement() error = %v, want DeadlineExceeded", err)
+	}
+	var we *WaitError
+	if !errors.As(err, &we) {
+		t.Fatalf("WaitForElement() error not a *WaitError: %v", err)
+	}
+	if we.Hierarchy == nil {
+		t.Fatal("WaitError.Hierarchy not recorded")
+	}
+	if _, ok := we.Hierarchy.Find(ByText("OK")); !ok {
+		t.Fatal("WaitError.Hierarchy is not the last dump")
+	}
+}
+
+func TestWaitForElement_AdbFailureIsNotRetried(t *testing.T) {
+	c, calls := fakeADBSeq(t, fakeResponse{stderr: "error: device offline\n", code: 1})
+	_, err := c.Device("S").WaitForElement(context.Background(), ByText("OK"))
+	if !errors.Is(err, ErrDeviceOffline) {
+		t.Fatalf("WaitForElement() error = %v, want ErrDeviceOffline", err)
+	}
+	if n := len(calls()); n != 1 {
+		t.Fatalf("WaitForElement() made %d calls, want 1", n)
+	}
+}
+
+func TestWaitForGone(t *testing.T) {
+	c, _ := fakeADBSeq(t,
+		fakeResponse{stdout: uiDump},
+		fakeResponse{stdout: `<hierarchy rotation="0"></hierarchy>`},
+	)
+	if err := c.Device("S").WaitForGone(context.Background(), ByText("OK")); err != nil {
+		t.Fatalf("WaitForGone() error = %v", err)
+	}
+}
+
+func TestWaitForIdle(t *testing.T) {
+	c, calls := fakeADBSeq(t,
+		fakeResponse{stdout: `<hierarchy rotation="0"><node text="a" /></hierarchy>`},
+		fakeResponse{stdout: uiDump},
+		fakeResponse{stdout: uiDump},
+	)
+	if err := c.Device("S").WaitForIdle(context.Background()); err != nil {
+		t.Fatalf("WaitForIdle() error = %v", err)
+	}
+	if n := len(calls()); n != 3 {
+		t.Fatalf("WaitForIdle() made %d dumps, want 3", n)
+	}
+}
+
+func TestWaitForActivity(t *testing.T) {
+	c, _ := fakeADBSeq(t,
+		fakeResponse{stdout: "  mResumedActivity: ActivityRecord{1a2b3c u0 com.android.launcher3/.Launcher t1}\n"},
+		fakeResponse{stdout: "  topResumedActivity=ActivityRecord{4d5e6f u0 com.example/com.example.MainActivity t42}\n"},
+	)
+	if err := c.Device("S").WaitForActivity(context.Background(), "com.example/.MainActivity"); err != nil {
+		t.Fatalf("WaitForActivity() error = %v", err)
+	}
+}
+
+func TestWaitForActivity_TimeoutReportsLast(t *testing.T) {
+	c, _ := fakeADBSeq(t, fakeResponse{stdout: "  ResumedActivity: ActivityRecord{1a2b3c u0 com.android.launcher3/.Launcher t1}\n"})
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	err := c.Device("S").WaitForActivity(ctx, "com.example")
+	var we *WaitError
+	if !errors.As(err, &we) {
+		t.Fatalf("WaitForActivity() error = %v, want *WaitError", err)
+	}
+	if we.Activity != "com.android.launcher3/.Launcher" {
+		t.Fatalf("WaitError.Activity = %q", we.Activity)
+	}
+}
+
+func TestSameComponent(t *testing.T) {
+	tests := []struct {
+		have, want string
+		match      bool
+	}{
+		{"com.example/.Main", "com.example/com.example.Main", true},
+		{"com.example/com.example.Main", "com.example/.Main", true},
+		{"com.example/.Main", "com.example", true},
+		{"com.example/.Main", "com.example/.Other", false},
+		{"com.example.beta/.Main", "com.example", false},
+		{"", "com.example", false},
+	}
+	for _, tt := range tests {
+		if got := sameComponent(tt.have, tt.want); got != tt.match {
+			t.Fatalf("sameComponent(%q, %q) = %v, want %v", tt.have, tt.want, got, tt.match)
+		}
+	}
+}
+
+func TestWaitFor(t *testing.T) {
+	tests := []struct {
+		transport Transport
+		state     WaitState
+		want      string
+	}{
+		{UnknownTransport, WaitDevice, "wait-for-device"},
+		{USB, WaitRecovery, "wait-for-usb-recovery"},
+		{Network, WaitDevice, "wait-for-local-device"},
+		{UnknownTransport, WaitDisconnect, "wait-for-disconnect"},
+		{USB, WaitSideload, "wait-for-usb-sideload"},
+	}
+	for _, tt := range tests {
+		c, argsFile := fakeADB(t, "", "", 0)
+		if err := c.Device("S").WaitFor(context.Background(), tt.transport, tt.state); err != nil {
+			t.Fatalf("WaitFor() error = %v", err)
+		}
+		want := []string{"-s", "S", tt.want}
+		if got := readArgs(t, argsFile); !reflect.DeepEqual(got, want) {
+			t.Fatalf("WaitFor(%v, %q) args = %v, want %v", tt.transport, tt.state, got, want)
+		}
+	}
+}
+
+func TestWaitForAny(t *testing.T) {
+	c, _ := fakeADBSeq(t,
+		fakeResponse{stdout: "List of devices attached\nOLD\tdevice\nNEW\tunauthorized\n"},
+		fakeResponse{stdout: "List of devices attached\nOLD\tdevice\nNEW\tunauthorized\n"},
+		fakeResponse{stdout: "List of devices attached\nOLD\tdevice\nNEW\tdevice\n"},
+	)
+	d, err := c.WaitForAny(context.Background())
+	if err != nil {
+		t.Fatalf("WaitForAny() error = %v", err)
+	}
+	if d.Serial() != "NEW" || !d.Authorized() {
+		t.Fatalf("WaitForAny() = %q (authorized %v), want NEW", d.Serial(), d.Authorized())
+	}
+}